@@ -0,0 +1,379 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/swag"
+	"github.com/go-openapi/spec"
+)
+
+// Version is the version of the AsyncAPI specification the documents are written in.
+const Version = "2.6.0"
+
+// Document is an AsyncAPI document. Its schemas are JSON schemas, like those of the OpenAPI documents.
+type Document struct {
+	AsyncAPI           string              `json:"asyncapi"`
+	Info               Info                `json:"info"`
+	DefaultContentType string              `json:"defaultContentType,omitempty"`
+	Channels           map[string]*Channel `json:"channels"`
+	Components         *Components         `json:"components,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Channel is a route on which messages are exchanged. Subscribe describes the messages the application sends,
+// and Publish the messages it receives.
+type Channel struct {
+	Description string                `json:"description,omitempty"`
+	Parameters  map[string]*Parameter `json:"parameters,omitempty"`
+	Subscribe   *Operation            `json:"subscribe,omitempty"`
+	Publish     *Operation            `json:"publish,omitempty"`
+	Bindings    *ChannelBindings      `json:"bindings,omitempty"`
+}
+
+type Parameter struct {
+	Description string       `json:"description,omitempty"`
+	Schema      *spec.Schema `json:"schema,omitempty"`
+}
+
+type Operation struct {
+	OperationID string             `json:"operationId,omitempty"`
+	Summary     string             `json:"summary,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Tags        []Tag              `json:"tags,omitempty"`
+	Bindings    *OperationBindings `json:"bindings,omitempty"`
+	Message     *Message           `json:"message,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type Message struct {
+	Name        string       `json:"name,omitempty"`
+	Title       string       `json:"title,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	ContentType string       `json:"contentType,omitempty"`
+	Payload     *spec.Schema `json:"payload,omitempty"`
+}
+
+type ChannelBindings struct {
+	WS *WebSocketsChannelBinding `json:"ws,omitempty"`
+}
+
+// WebSocketsChannelBinding describes the handshake of a WebSocket channel.
+type WebSocketsChannelBinding struct {
+	Method         string       `json:"method,omitempty"`
+	Query          *spec.Schema `json:"query,omitempty"`
+	Headers        *spec.Schema `json:"headers,omitempty"`
+	BindingVersion string       `json:"bindingVersion,omitempty"`
+}
+
+type OperationBindings struct {
+	HTTP *HTTPOperationBinding `json:"http,omitempty"`
+}
+
+// HTTPOperationBinding describes the HTTP request that opens a channel, e.g. a Server-Sent Events stream.
+type HTTPOperationBinding struct {
+	Type           string       `json:"type"`
+	Method         string       `json:"method,omitempty"`
+	Query          *spec.Schema `json:"query,omitempty"`
+	BindingVersion string       `json:"bindingVersion,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*spec.Schema `json:"schemas,omitempty"`
+}
+
+const (
+	wsBindingVersion   = "0.1.0"
+	httpBindingVersion = "0.1.0"
+)
+
+func New() *Document {
+	return &Document{
+		AsyncAPI:           Version,
+		DefaultContentType: "application/json",
+		Channels:           map[string]*Channel{},
+		Components:         &Components{},
+	}
+}
+
+type Route = openapi2.Route
+
+// Docs documents the Server-Sent Events and WebSocket routes as channels. The other routes are skipped.
+// The info of the document is that of the parser's swagger, like in the OpenAPI documents.
+func Docs(routes []*Route, opts ...openapi2.Option) (*Document, error) {
+	parser := openapi2.NewParser()
+	doc := New()
+
+	for _, route := range routes {
+		err := RegisterRoute(parser, doc, route, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	doc.Info = info(parser.GetSwagger().Info)
+
+	return doc.withDefinitions(parser.GetSwagger().Definitions)
+}
+
+func info(i *spec.Info) Info {
+	if i == nil {
+		return Info{}
+	}
+
+	return Info{Title: i.Title, Version: i.Version, Description: i.Description}
+}
+
+// RegisterRoute adds the channel of the route to doc if its handler streams Server-Sent Events or is a WebSocket handler.
+// The definitions of the message types are added to the parser's swagger.
+func RegisterRoute(parser *swag.Parser, doc *Document, route *Route, opts ...openapi2.Option) error {
+	h := route.Handler
+
+	wser, isWebSocket := h.(chai.WebSocketer)
+	if !isWebSocket && !isSSE(h) {
+		return nil
+	}
+
+	hd, err := openapi2.ParseHandler(parser, route, opts...)
+	if err != nil {
+		return err
+	}
+
+	ch := &Channel{
+		Description: hd.Description,
+		Parameters:  pathParameters(hd.Parameters),
+	}
+
+	if isWebSocket {
+		in, out := wser.Messages()
+
+		ch.Publish, err = operation(hd, in)
+		if err != nil {
+			return err
+		}
+
+		ch.Subscribe, err = operation(hd, out)
+		if err != nil {
+			return err
+		}
+
+		ch.Bindings = &ChannelBindings{
+			WS: &WebSocketsChannelBinding{
+				Method:         http.MethodGet,
+				Query:          paramsSchema(hd.Parameters, "query"),
+				Headers:        paramsSchema(hd.Parameters, "header"),
+				BindingVersion: wsBindingVersion,
+			},
+		}
+	} else {
+		ch.Subscribe, err = operation(hd, h.(chai.ResErrer).Res())
+		if err != nil {
+			return err
+		}
+
+		ch.Subscribe.Bindings = &OperationBindings{
+			HTTP: &HTTPOperationBinding{
+				Type:           "request",
+				Method:         route.Method,
+				Query:          paramsSchema(hd.Parameters, "query"),
+				BindingVersion: httpBindingVersion,
+			},
+		}
+	}
+
+	doc.Channels[route.Path] = ch
+
+	return nil
+}
+
+// AddWebhooks adds a channel for each event of wr, on which the application sends the payloads of the event to the webhook receivers.
+func AddWebhooks(doc *Document, wr *chai.WebhookRegistry, opts ...openapi2.Option) error {
+	parser := openapi2.NewParser()
+
+	for _, ev := range wr.Events() {
+		schema, err := openapi2.FileSchema(parser, ev.File, reflect.New(ev.Type).Interface(), opts...)
+		if err != nil {
+			return err
+		}
+
+		doc.Channels[ev.Name] = &Channel{
+			Description: ev.Description,
+			Subscribe: &Operation{
+				OperationID: ev.Name,
+				Bindings: &OperationBindings{
+					HTTP: &HTTPOperationBinding{
+						Type:           "request",
+						Method:         http.MethodPost,
+						BindingVersion: httpBindingVersion,
+					},
+				},
+				Message: &Message{
+					Name:        ev.Name,
+					ContentType: "application/json",
+					Payload:     schema,
+				},
+			},
+		}
+	}
+
+	res, err := doc.withDefinitions(parser.GetSwagger().Definitions)
+	if err != nil {
+		return err
+	}
+
+	*doc = *res
+
+	return nil
+}
+
+func isSSE(h http.Handler) bool {
+	st, ok := h.(chai.Streamer)
+	if !ok {
+		return false
+	}
+
+	_, ok = h.(chai.ResErrer)
+
+	return ok && len(st.StreamContentTypes()) > 0 && st.StreamContentTypes()[0] == chai.EventStreamContentType
+}
+
+// operation returns the operation of a channel whose messages are of the type payload points to.
+func operation(hd *openapi2.HandlerDocs, payload any) (*Operation, error) {
+	schema, err := hd.Schema(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &Operation{
+		OperationID: hd.ID,
+		Summary:     hd.Summary,
+		Message: &Message{
+			Name:        messageName(payload),
+			ContentType: "application/json",
+			Payload:     schema,
+		},
+	}
+
+	for _, tag := range hd.Tags {
+		op.Tags = append(op.Tags, Tag{Name: tag})
+	}
+
+	return op, nil
+}
+
+// messageName returns the name of the type payload points to, without its package.
+func messageName(payload any) string {
+	t := reflect.TypeOf(payload)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+func pathParameters(params []spec.Parameter) map[string]*Parameter {
+	res := map[string]*Parameter{}
+
+	for _, p := range params {
+		if p.In != "path" {
+			continue
+		}
+
+		res[p.Name] = &Parameter{Description: p.Description, Schema: paramSchema(p)}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// paramsSchema returns the object schema of the parameters that are in in, or nil if there are none.
+func paramsSchema(params []spec.Parameter, in string) *spec.Schema {
+	var res *spec.Schema
+
+	for _, p := range params {
+		if p.In != in {
+			continue
+		}
+
+		if res == nil {
+			res = &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, Properties: spec.SchemaProperties{}}}
+		}
+
+		res.Properties[p.Name] = *paramSchema(p)
+		if p.Required {
+			res.Required = append(res.Required, p.Name)
+		}
+	}
+
+	return res
+}
+
+func paramSchema(p spec.Parameter) *spec.Schema {
+	s := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:        spec.StringOrArray{p.Type},
+			Format:      p.Format,
+			Description: p.Description,
+			Pattern:     p.Pattern,
+			Enum:        p.Enum,
+			Default:     p.Default,
+			Minimum:     p.Minimum,
+			Maximum:     p.Maximum,
+			MinLength:   p.MinLength,
+			MaxLength:   p.MaxLength,
+		},
+	}
+
+	if p.Items != nil {
+		s.Items = &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{p.Items.Type}, Format: p.Items.Format}}}
+	}
+
+	return s
+}
+
+// withDefinitions returns a copy of doc with the definitions added to its component schemas, and with the references
+// to the definitions pointing to the component schemas.
+func (doc *Document) withDefinitions(definitions spec.Definitions) (*Document, error) {
+	for name, schema := range definitions {
+		if doc.Components.Schemas == nil {
+			doc.Components.Schemas = map[string]*spec.Schema{}
+		}
+
+		if _, ok := doc.Components.Schemas[name]; ok {
+			continue
+		}
+
+		schema := schema
+		doc.Components.Schemas[name] = &schema
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	b = []byte(strings.ReplaceAll(string(b), `"#/definitions/`, `"#/components/schemas/`))
+
+	res := new(Document)
+	err = json.Unmarshal(b, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,123 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/internal/tests"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		info *spec.Info
+		want Info
+	}{
+		{
+			name: "no info",
+			want: Info{},
+		},
+		{
+			name: "general API info",
+			info: &spec.Info{InfoProps: spec.InfoProps{Title: "Events API", Version: "1.0", Description: "The events"}},
+			want: Info{Title: "Events API", Version: "1.0", Description: "The events"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, info(tt.info))
+		})
+	}
+}
+
+func TestDocs(t *testing.T) {
+	type args struct {
+		routes   []*Route
+		webhooks *chai.WebhookRegistry
+	}
+	tests := []struct {
+		name     string
+		args     args
+		filePath string
+		wantErr  bool
+	}{
+		{
+			name: "t1",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/events/{topic}",
+						Params: []spec.Parameter{*spec.PathParam("topic").Typed("string", "")},
+						Handler: chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[*tests.TestEvent], int, error) {
+							return nil, 0, nil
+						}),
+					},
+					{
+						Method: "GET",
+						Path:   "/rooms/{id}",
+						Handler: chai.NewWebSocketHandler(func(req *tests.TestParamsOnlyRequest, conn *chai.WebSocketConn[*tests.TestRequest, *tests.TestResponse], r *http.Request) error {
+							return nil
+						}),
+					},
+					{
+						Method: "GET",
+						Path:   "/accounts",
+						Handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}),
+					},
+				},
+			},
+			filePath: "testdata/t1.json",
+			wantErr:  false,
+		},
+		{
+			name: "t2",
+			args: args{
+				routes:   []*Route{},
+				webhooks: testWebhooks(),
+			},
+			filePath: "testdata/t2.json",
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Docs(tt.args.routes)
+			if err == nil && tt.args.webhooks != nil {
+				err = AddWebhooks(got, tt.args.webhooks)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Docs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			require.JSONEq(t, load(t, tt.filePath), js(got))
+		})
+	}
+}
+
+func testWebhooks() *chai.WebhookRegistry {
+	wr := chai.NewWebhookRegistry()
+	chai.RegisterWebhook[*tests.TestEvent](wr, "event.created", "Sent when an event is created")
+	chai.RegisterWebhook[tests.TestResponse](wr, "response.sent", "")
+
+	return wr
+}
+
+func js(v any) string {
+	b, _ := json.MarshalIndent(v, "", "  ")
+	return string(b)
+}
+
+func load(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(b)
+}
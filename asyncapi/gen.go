@@ -0,0 +1,31 @@
+package asyncapi
+
+import (
+	"github.com/go-chai/chai/internal/docgen"
+	"github.com/go-chai/swag/gen"
+)
+
+type GenConfig = gen.GenConfig
+
+// WriteDocs writes doc to asyncapi.json and asyncapi.yaml in the OutputDir of cfg.
+func WriteDocs(doc *Document, cfg *GenConfig) error {
+	return NewGen().Generate(doc, cfg)
+}
+
+// Gen writes AsyncAPI documents.
+type Gen struct {
+	writer *docgen.Writer
+}
+
+func NewGen() *Gen {
+	return &Gen{writer: docgen.NewWriter()}
+}
+
+// Generate writes doc to asyncapi.json and asyncapi.yaml in the OutputDir of config, "docs/" by default.
+func (g *Gen) Generate(doc *Document, config *GenConfig) error {
+	if config.OutputDir == "" {
+		config.OutputDir = "docs/"
+	}
+
+	return g.writer.Write(doc, config.OutputDir, "asyncapi")
+}
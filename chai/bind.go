@@ -0,0 +1,294 @@
+package chai
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+)
+
+var paramLocations = []string{InPath, InQuery, InHeader, InCookie}
+
+// ParamField is a request struct field that is bound from the path, query, headers or cookies instead of the body.
+type ParamField struct {
+	In    string
+	Name  string
+	Field reflect.StructField
+	Index []int
+}
+
+// BindError is returned when a path, query, header or cookie value cannot be converted to the type of its field.
+type BindError struct {
+	In   string `json:"in"`
+	Name string `json:"name"`
+	Err  error  `json:"-"`
+}
+
+func (e *BindError) Error() string {
+	return fmt.Sprintf("invalid %s parameter %q: %s", e.In, e.Name, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// ParamFields returns the fields of t (or of the struct t points to) that are tagged
+// with one of `path`, `query`, `header` or `cookie`, including those of embedded structs.
+func ParamFields(t reflect.Type) []ParamField {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	res := make([]ParamField, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct && !hasParamTag(f) {
+			for _, pf := range ParamFields(f.Type) {
+				pf.Index = append([]int{i}, pf.Index...)
+				res = append(res, pf)
+			}
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		for _, in := range paramLocations {
+			name, ok := f.Tag.Lookup(in)
+			if !ok || name == "" || name == "-" {
+				continue
+			}
+
+			res = append(res, ParamField{
+				In:    in,
+				Name:  name,
+				Field: f,
+				Index: []int{i},
+			})
+			break
+		}
+	}
+
+	return res
+}
+
+// HasBody reports whether t (or the struct t points to) has any fields that are read from the request body.
+func HasBody(t reflect.Type) bool {
+	t = indirectType(t)
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct && !hasParamTag(f) {
+			if HasBody(f.Type) {
+				return true
+			}
+			continue
+		}
+
+		if !f.IsExported() || hasParamTag(f) || f.Tag.Get("json") == "-" {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func hasParamTag(f reflect.StructField) bool {
+	for _, in := range paramLocations {
+		if _, ok := f.Tag.Lookup(in); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// paramKey identifies a parameter by its location and name.
+type paramKey struct {
+	in   string
+	name string
+}
+
+// paramKeyOf returns the key of the parameter a field is bound from, or the zero key if it is not bound from a parameter.
+func paramKeyOf(f reflect.StructField) paramKey {
+	for _, in := range paramLocations {
+		if name, ok := f.Tag.Lookup(in); ok {
+			return paramKey{in, name}
+		}
+	}
+
+	return paramKey{}
+}
+
+// bind sets the param fields of v from r, and returns the parameters that r has no values for.
+func bind(v reflect.Value, r *http.Request, pathParam PathParamFunc) (map[paramKey]bool, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	absent := map[paramKey]bool{}
+
+	for _, pf := range ParamFields(v.Type()) {
+		values := paramValues(r, pf, pathParam)
+		if len(values) == 0 {
+			absent[paramKey{pf.In, pf.Name}] = true
+			continue
+		}
+
+		fv, err := fieldByIndex(v, pf.Index)
+		if err != nil {
+			return nil, &BindError{In: pf.In, Name: pf.Name, Err: err}
+		}
+
+		err = setValues(fv, values)
+		if err != nil {
+			return nil, &BindError{In: pf.In, Name: pf.Name, Err: err}
+		}
+	}
+
+	return absent, nil
+}
+
+func paramValues(r *http.Request, pf ParamField, pathParam PathParamFunc) []string {
+	switch pf.In {
+	case InPath:
+		if pathParam == nil {
+			return nil
+		}
+		if s := pathParam(r, pf.Name); s != "" {
+			return []string{s}
+		}
+	case InQuery:
+		return r.URL.Query()[pf.Name]
+	case InHeader:
+		return r.Header.Values(pf.Name)
+	case InCookie:
+		if c, err := r.Cookie(pf.Name); err == nil {
+			return []string{c.Value}
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but allocates nil embedded struct pointers along the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					if !v.CanSet() {
+						return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", v.Type().Elem())
+					}
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+
+	return v, nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func setValues(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return setValues(v.Elem(), values)
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+	}
+
+	if v.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i := range values {
+			err := setValues(s.Index(i), values[i:i+1])
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+
+		return nil
+	}
+
+	return setValue(v, values[0])
+}
+
+func setValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
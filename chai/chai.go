@@ -14,6 +14,14 @@ type Reqer interface {
 	Req() any
 }
 
+type BodyDecoder interface {
+	DecodesBody() bool
+}
+
+type StrictBodyDecoder interface {
+	DecodesStrictly() bool
+}
+
 type ResErrer interface {
 	Res() any
 	Err() any
@@ -23,14 +31,25 @@ type Handlerer interface {
 	Handler() any
 }
 
-func write(w http.ResponseWriter, code int, v any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(v)
+type ContentTyper interface {
+	ContentTypes() []string
 }
 
-func writeErr(w http.ResponseWriter, code int, e ErrType) {
-	DefaultErrorWriter.WriteError(w, code, e)
+// RequestContentTyper is implemented by handlers whose request bodies can have other media types than their responses.
+type RequestContentTyper interface {
+	RequestContentTypes() []string
+}
+
+// Streamer is implemented by handlers that stream their response, such as SSEHandler and StreamHandler.
+// Their response schema describes a single element of the stream, which is written with one of StreamContentTypes.
+type Streamer interface {
+	StreamContentTypes() []string
+}
+
+func write(w http.ResponseWriter, codec Codec, code int, v any) {
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.WriteHeader(code)
+	codec.Encode(w, v)
 }
 
 func writeBytes(w http.ResponseWriter, code int, bytes []byte) {
@@ -73,6 +92,26 @@ type ErrorWriter interface {
 	WriteError(w http.ResponseWriter, code int, e ErrType)
 }
 
+// ErrorTyper is implemented by error writers that wrap every error in the same response type, which is then used to document the error responses.
+type ErrorTyper interface {
+	ErrorType() any
+}
+
+// ErrorContentTyper is implemented by error writers to document the media type of the error responses they write.
+// The error responses of the writers that do not implement it are documented as application/json.
+type ErrorContentTyper interface {
+	ErrorContentType() string
+}
+
+type ErrorWriterer interface {
+	ErrorWriter() ErrorWriter
+}
+
+// ErrorRegistryer is implemented by handlers so that the mappings of their ErrorRegistry can be documented.
+type ErrorRegistryer interface {
+	ErrorRegistry() *ErrorRegistry
+}
+
 type defaultErrorWriter struct{}
 
 func (defaultErrorWriter) WriteError(w http.ResponseWriter, code int, e ErrType) {
@@ -84,10 +123,20 @@ func (defaultErrorWriter) WriteError(w http.ResponseWriter, code int, e ErrType)
 
 	b, err := json.Marshal(ew)
 	if err != nil {
-		panic(err)
+		// The error itself cannot be marshaled, so only its text is written.
+		b, _ = json.Marshal(map[string]any{
+			"error":       e.Error(),
+			"status_code": code,
+		})
 	}
 
 	writeBytes(w, code, b)
 }
 
-var DefaultErrorWriter = &defaultErrorWriter{}
+func (defaultErrorWriter) ErrorContentType() string {
+	return "application/json"
+}
+
+// DefaultErrorWriter is used by handlers that are not configured with their own ErrorWriter.
+// Handlers pick it up when they are created, so changing it does not affect handlers created before.
+var DefaultErrorWriter ErrorWriter = &defaultErrorWriter{}
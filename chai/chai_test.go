@@ -2,18 +2,29 @@ package chai_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chai/chai/chai"
+	chaichi "github.com/go-chai/chai/chi"
+	chaigorilla "github.com/go-chai/chai/gorilla"
 	"github.com/go-chai/chai/internal/tests"
 	"github.com/go-chai/chai/internal/tests/xrequire"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
 )
 
 func newRes() *tests.TestResponse {
@@ -247,3 +258,1810 @@ func TestHandlers(t *testing.T) {
 		})
 	}
 }
+
+func TestBind(t *testing.T) {
+	pathParam := func(r *http.Request, name string) string {
+		if name == "id" {
+			return "42"
+		}
+		return ""
+	}
+
+	tcs := []struct {
+		name     string
+		makeReq  func() *http.Request
+		response string
+	}{
+		{
+			name: "all locations",
+			makeReq: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/items/42?q=abc&tags=a&tags=b", bytes.NewBufferString(`{"foo":"bar"}`))
+				r.Header.Set("Authorization", "token")
+				r.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+				return r
+			},
+			response: `{"ID":42,"Q":"abc","Tags":["a","b"],"Auth":"token","Session":"s1","Foo":"bar"}`,
+		},
+		{
+			name: "missing values are left empty",
+			makeReq: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/items/42", bytes.NewBufferString(`{"foo":"bar"}`))
+			},
+			response: `{"ID":42,"Q":"","Tags":null,"Auth":"","Session":"","Foo":"bar"}`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			h := chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (map[string]any, int, error) {
+				return map[string]any{
+					"ID":      req.ID,
+					"Q":       req.Q,
+					"Tags":    req.Tags,
+					"Auth":    req.Auth,
+					"Session": req.Session,
+					"Foo":     req.Foo,
+				}, http.StatusOK, nil
+			}, chai.WithPathParamFunc(pathParam))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.makeReq())
+			xrequire.JSONEq(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestBindError(t *testing.T) {
+	h := chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return "ok", http.StatusOK, nil
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/?limit=abc", bytes.NewBufferString(`{}`)))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	xrequire.JSONEq(t, `{"error":"invalid query parameter \"limit\": strconv.ParseInt: parsing \"abc\": invalid syntax", "in":"query", "name":"limit", "status_code":400}`, w.Body.String())
+}
+
+func TestWithoutBody(t *testing.T) {
+	h := chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestParamsOnlyRequest, int, error) {
+		return req, http.StatusOK, nil
+	}, chai.WithoutBody(), chai.WithPathParamFunc(func(r *http.Request, name string) string {
+		return "7"
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/items/7?limit=3", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	xrequire.JSONEq(t, `{"ID":7,"Limit":3,"Trace":"","Score":0}`, w.Body.String())
+}
+
+func TestParamsOnlyRequest(t *testing.T) {
+	h := chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestParamsOnlyRequest, int, error) {
+		return req, http.StatusOK, nil
+	}, chai.WithPathParamFunc(func(r *http.Request, name string) string {
+		return "7"
+	}))
+
+	tcs := []struct {
+		name string
+		body io.Reader
+	}{
+		{name: "empty body", body: nil},
+		{name: "ignored body", body: bytes.NewBufferString(`{"foo":"bar"}`)},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/items/7?limit=3", tt.body))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			xrequire.JSONEq(t, `{"ID":7,"Limit":3,"Trace":"","Score":0}`, w.Body.String())
+		})
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	h := chai.NewReqResHandler(func(req *tests.TestStruct, w http.ResponseWriter, r *http.Request) (*tests.TestStruct, int, error) {
+		return req, http.StatusOK, nil
+	}, chai.WithCodecs(chai.JSON, chai.XML, chai.Form, chai.MsgPack))
+
+	msgpackBody := new(bytes.Buffer)
+	require.NoError(t, chai.MsgPack.Encode(msgpackBody, &tests.TestStruct{Foo: "m", Bar: 3}))
+
+	tcs := []struct {
+		name        string
+		contentType string
+		accept      string
+		body        io.Reader
+		code        int
+		resType     string
+		response    string
+	}{
+		{
+			name:     "defaults to the first codec",
+			body:     bytes.NewBufferString(`{"foo":"a","bar":1}`),
+			code:     http.StatusOK,
+			resType:  "application/json",
+			response: "{\"foo\":\"a\",\"bar\":1}\n",
+		},
+		{
+			name:        "xml in, json out",
+			contentType: "application/xml; charset=utf-8",
+			accept:      "application/json",
+			body:        bytes.NewBufferString(`<TestStruct><Foo>x</Foo><Bar>2</Bar></TestStruct>`),
+			code:        http.StatusOK,
+			resType:     "application/json",
+			response:    "{\"foo\":\"x\",\"bar\":2}\n",
+		},
+		{
+			name:        "form in, xml out by quality",
+			contentType: "application/x-www-form-urlencoded",
+			accept:      "application/json;q=0.5, application/xml",
+			body:        bytes.NewBufferString(`foo=f&bar=5`),
+			code:        http.StatusOK,
+			resType:     "application/xml",
+			response:    `<TestStruct><Foo>f</Foo><Bar>5</Bar></TestStruct>`,
+		},
+		{
+			name:        "msgpack in, form out",
+			contentType: "application/msgpack",
+			accept:      "application/x-www-form-urlencoded",
+			body:        msgpackBody,
+			code:        http.StatusOK,
+			resType:     "application/x-www-form-urlencoded",
+			response:    `bar=3&foo=m`,
+		},
+		{
+			name:        "unsupported media type",
+			contentType: "text/csv",
+			body:        bytes.NewBufferString(`a,b`),
+			code:        http.StatusUnsupportedMediaType,
+			resType:     "application/json",
+			response:    "{\"error\":\"unsupported media type: text/csv\",\"status_code\":415}",
+		},
+		{
+			name:     "not acceptable",
+			accept:   "text/html",
+			body:     bytes.NewBufferString(`{}`),
+			code:     http.StatusNotAcceptable,
+			resType:  "application/json",
+			response: "{\"error\":\"not acceptable: text/html\",\"status_code\":406}",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", tt.body)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.resType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestTextCodec(t *testing.T) {
+	h := chai.NewReqResHandler(func(req string, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return "hello " + req, http.StatusOK, nil
+	}, chai.WithCodecs(chai.Text))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("world")))
+
+	require.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	require.Equal(t, "hello world", w.Body.String())
+}
+
+func TestValidate(t *testing.T) {
+	h := chai.NewReqResHandler(func(req *tests.TestValidatedRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return "ok", http.StatusOK, nil
+	})
+
+	tcs := []struct {
+		name     string
+		url      string
+		body     string
+		code     int
+		response string
+	}{
+		{
+			name:     "valid",
+			url:      "/?limit=10",
+			body:     `{"name":"n","email":"a@b.c","code":"ABC","kind":"a","age":20,"items":[{"id":1}],"parent":{"id":2}}`,
+			code:     http.StatusOK,
+			response: `"ok"`,
+		},
+		{
+			name: "invalid",
+			url:  "/?limit=101",
+			body: `{"name":"0123456789a","email":"nope","code":"abcd","kind":"c","age":17,"items":[{"id":1},{"id":0},{"id":3}],"parent":{}}`,
+			code: http.StatusUnprocessableEntity,
+			response: `{
+				"error": "validation failed: limit must be at most 100; name must have a length of at most 10; email must be a valid email; code must have a length of 3; code must match ^[A-Z]{3}$; kind must be one of [a, b]; age must be at least 18; items must have a length of at most 2; items[1].id is required; parent.id is required",
+				"status_code": 422,
+				"errors": [
+					{"field": "limit", "rule": "max", "message": "must be at most 100"},
+					{"field": "name", "rule": "max", "message": "must have a length of at most 10"},
+					{"field": "email", "rule": "format", "message": "must be a valid email"},
+					{"field": "code", "rule": "len", "message": "must have a length of 3"},
+					{"field": "code", "rule": "pattern", "message": "must match ^[A-Z]{3}$"},
+					{"field": "kind", "rule": "enum", "message": "must be one of [a, b]"},
+					{"field": "age", "rule": "min", "message": "must be at least 18"},
+					{"field": "items", "rule": "max", "message": "must have a length of at most 2"},
+					{"field": "items[1].id", "rule": "required", "message": "is required"},
+					{"field": "parent.id", "rule": "required", "message": "is required"}
+				]
+			}`,
+		},
+		{
+			name: "zero values",
+			url:  "/?limit=0",
+			body: `{"name":"n","email":"","code":"ABC","kind":"","age":0}`,
+			code: http.StatusUnprocessableEntity,
+			response: `{
+				"error": "validation failed: limit must be at least 1; email must be a valid email; kind must be one of [a, b]; age must be at least 18",
+				"status_code": 422,
+				"errors": [
+					{"field": "limit", "rule": "min", "message": "must be at least 1"},
+					{"field": "email", "rule": "format", "message": "must be a valid email"},
+					{"field": "kind", "rule": "enum", "message": "must be one of [a, b]"},
+					{"field": "age", "rule": "min", "message": "must be at least 18"}
+				]
+			}`,
+		},
+		{
+			name:     "absent optional param",
+			url:      "/",
+			body:     `{"name":"n","email":"a@b.c","code":"ABC","kind":"b","age":18}`,
+			code:     http.StatusOK,
+			response: `"ok"`,
+		},
+		{
+			name:     "absent optional pointers",
+			url:      "/?limit=1",
+			body:     `{"name":"n","email":"a@b.c","code":"ABC","kind":"b","age":18}`,
+			code:     http.StatusOK,
+			response: `"ok"`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.url, bytes.NewBufferString(tt.body)))
+
+			require.Equal(t, tt.code, w.Code)
+			xrequire.JSONEq(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+var notFoundProblem = &chai.Problem{Title: "Not Found", Status: http.StatusNotFound}
+
+func TestProblemErrorWriter(t *testing.T) {
+	tcs := []struct {
+		name     string
+		handler  http.Handler
+		code     int
+		response string
+	}{
+		{
+			name: "plain error",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+				return "", http.StatusNotFound, errors.New("account 3 not found")
+			}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+			code:     http.StatusNotFound,
+			response: `{"type":"about:blank", "title":"Not Found", "status":404, "detail":"account 3 not found"}`,
+		},
+		{
+			name: "error struct members become extensions",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, *tests.TestErrorPtr) {
+				return "", http.StatusConflict, &tests.TestErrorPtr{Message: "zz"}
+			}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+			code:     http.StatusConflict,
+			response: `{"type":"about:blank", "title":"Conflict", "status":409, "detail":"zz", "message":"zz"}`,
+		},
+		{
+			name: "problem returned by the handler",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, *chai.Problem) {
+				return "", http.StatusForbidden, &chai.Problem{Type: "https://example.com/probs/out-of-credit", Title: "You do not have enough credit.", Status: http.StatusForbidden, Instance: "/account/12345", Extensions: map[string]any{"balance": 30}}
+			}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+			code:     http.StatusForbidden,
+			response: `{"type":"https://example.com/probs/out-of-credit", "title":"You do not have enough credit.", "status":403, "instance":"/account/12345", "balance":30}`,
+		},
+		{
+			name: "problem status is the status code",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, *chai.Problem) {
+				return "", http.StatusInternalServerError, notFoundProblem
+			}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+			code:     http.StatusInternalServerError,
+			response: `{"title":"Not Found", "status":500}`,
+		},
+		{
+			name: "validation errors",
+			handler: chai.NewReqResHandler(func(req *tests.TestValidatedItem, w http.ResponseWriter, r *http.Request) (string, int, error) {
+				return "", http.StatusOK, nil
+			}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+			code:     http.StatusUnprocessableEntity,
+			response: `{"type":"about:blank", "title":"Unprocessable Entity", "status":422, "detail":"validation failed: id is required", "errors":[{"field":"id", "rule":"required", "message":"is required"}]}`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{}`)))
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			xrequire.JSONEq(t, tt.response, w.Body.String())
+		})
+	}
+
+	require.Equal(t, http.StatusNotFound, notFoundProblem.Status, "the problem returned by the handler is not modified")
+}
+
+func TestOptions(t *testing.T) {
+	type hookCall struct {
+		code int
+		err  string
+	}
+
+	var calls []hookCall
+	hook := func(r *http.Request, code int, err error) {
+		calls = append(calls, hookCall{code, err.Error()})
+	}
+
+	cfg := chai.NewConfig(chai.WithErrorWriter(chai.ProblemErrorWriter{}), chai.WithErrorHook(hook), chai.WithMaxBodyBytes(16))
+	postFn := func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return req.Foo, http.StatusOK, nil
+	}
+	getFn := func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return "", http.StatusNotFound, errors.New("not found")
+	}
+
+	chiRouter := chi.NewRouter()
+	api := chaichi.NewRouter(chiRouter, cfg)
+	chaichi.Post(api, "/limited", postFn)
+	chaichi.Post(api, "/unlimited", postFn, chai.WithMaxBodyBytes(0))
+	chaichi.Get(api, "/problem", getFn)
+	chaichi.Get(api, "/default", getFn, chai.WithErrorWriter(chai.DefaultErrorWriter))
+	chaichi.Get(chiRouter, "/unconfigured", getFn)
+	api.Route("/route", func(r chi.Router) {
+		chaichi.Get(r, "/problem", getFn)
+	})
+	api.Group(func(r chi.Router) {
+		chaichi.Get(r, "/group/problem", getFn)
+	})
+	chaichi.Get(api.With(middleware.NoCache), "/with/problem", getFn)
+	mounted := chaichi.NewRouter(chi.NewRouter(), cfg)
+	chaichi.Get(mounted, "/problem", getFn)
+	api.Mount("/mount", mounted)
+
+	tcs := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		code        int
+		contentType string
+		hookCalls   []hookCall
+	}{
+		{
+			name:        "body within the limit",
+			method:      http.MethodPost,
+			path:        "/limited",
+			body:        `{"foob":"f"}`,
+			code:        http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:        "body over the limit",
+			method:      http.MethodPost,
+			path:        "/limited",
+			body:        `{"foob":"` + strings.Repeat("f", 32) + `"}`,
+			code:        http.StatusRequestEntityTooLarge,
+			contentType: "application/problem+json",
+			hookCalls:   []hookCall{{http.StatusRequestEntityTooLarge, "http: request body too large"}},
+		},
+		{
+			name:        "handler option overrides the config",
+			method:      http.MethodPost,
+			path:        "/unlimited",
+			body:        `{"foob":"` + strings.Repeat("f", 32) + `"}`,
+			code:        http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:        "error writer inherited from the config",
+			method:      http.MethodGet,
+			path:        "/problem",
+			code:        http.StatusNotFound,
+			contentType: "application/problem+json",
+			hookCalls:   []hookCall{{http.StatusNotFound, "not found"}},
+		},
+		{
+			name:        "error writer set on the handler",
+			method:      http.MethodGet,
+			path:        "/default",
+			code:        http.StatusNotFound,
+			contentType: "application/json",
+			hookCalls:   []hookCall{{http.StatusNotFound, "not found"}},
+		},
+		{
+			name:        "config inherited by Route",
+			method:      http.MethodGet,
+			path:        "/route/problem",
+			code:        http.StatusNotFound,
+			contentType: "application/problem+json",
+			hookCalls:   []hookCall{{http.StatusNotFound, "not found"}},
+		},
+		{
+			name:        "config inherited by Group",
+			method:      http.MethodGet,
+			path:        "/group/problem",
+			code:        http.StatusNotFound,
+			contentType: "application/problem+json",
+			hookCalls:   []hookCall{{http.StatusNotFound, "not found"}},
+		},
+		{
+			name:        "config inherited by With",
+			method:      http.MethodGet,
+			path:        "/with/problem",
+			code:        http.StatusNotFound,
+			contentType: "application/problem+json",
+			hookCalls:   []hookCall{{http.StatusNotFound, "not found"}},
+		},
+		{
+			name:        "mounted router",
+			method:      http.MethodGet,
+			path:        "/mount/problem",
+			code:        http.StatusNotFound,
+			contentType: "application/problem+json",
+			hookCalls:   []hookCall{{http.StatusNotFound, "not found"}},
+		},
+		{
+			name:        "handler registered on the unwrapped router",
+			method:      http.MethodGet,
+			path:        "/unconfigured",
+			code:        http.StatusNotFound,
+			contentType: "application/json",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+
+			w := httptest.NewRecorder()
+			chiRouter.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.hookCalls, calls)
+		})
+	}
+}
+
+func TestSubrouterConfig(t *testing.T) {
+	cfg := chai.NewConfig(chai.WithErrorWriter(chai.ProblemErrorWriter{}))
+	getFn := func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return "", http.StatusNotFound, errors.New("not found")
+	}
+
+	muxRouter := mux.NewRouter()
+	api := chaigorilla.NewRouter(muxRouter, cfg)
+	chaigorilla.Get(api.PathPrefix("/prefix").Subrouter(), "/problem", getFn)
+	chaigorilla.Get(api.Host("example.com").PathPrefix("/host").Subrouter(), "/problem", getFn)
+	chaigorilla.Get(muxRouter.PathPrefix("/unconfigured").Subrouter(), "/problem", getFn)
+
+	tcs := []struct {
+		name        string
+		url         string
+		contentType string
+	}{
+		{name: "PathPrefix", url: "/prefix/problem", contentType: "application/problem+json"},
+		{name: "chained matchers", url: "http://example.com/host/problem", contentType: "application/problem+json"},
+		{name: "unwrapped router", url: "/unconfigured/problem", contentType: "application/json"},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			require.Equal(t, http.StatusNotFound, w.Code)
+			require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+var errNotFound = errors.New("not found")
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return http.StatusText(e.code)
+}
+
+func (e *statusError) StatusCode() int {
+	return e.code
+}
+
+func TestErrorRegistry(t *testing.T) {
+	registry := chai.NewErrorRegistry().Register(errNotFound, http.StatusNotFound, "")
+	chai.RegisterErrorType[*tests.TestErrorPtr](registry, http.StatusConflict, "the account is locked")
+
+	tcs := []struct {
+		name     string
+		err      error
+		code     int
+		response string
+	}{
+		{
+			name:     "sentinel",
+			err:      fmt.Errorf("account 3: %w", errNotFound),
+			code:     http.StatusNotFound,
+			response: `{"error":"account 3: not found", "status_code":404}`,
+		},
+		{
+			name:     "type with a public message",
+			err:      fmt.Errorf("wrapped: %w", &tests.TestErrorPtr{Message: "row lock held by tx 42"}),
+			code:     http.StatusConflict,
+			response: `{"error":"the account is locked", "status_code":409}`,
+		},
+		{
+			name:     "status coder",
+			err:      &statusError{code: http.StatusTooManyRequests},
+			code:     http.StatusTooManyRequests,
+			response: `{"error":"Too Many Requests", "status_code":429}`,
+		},
+		{
+			name:     "unmapped",
+			err:      errors.New("boom"),
+			code:     http.StatusInternalServerError,
+			response: `{"error":"boom", "status_code":500}`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			h := chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+				return "", 0, tt.err
+			}, chai.WithErrors(registry))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			require.Equal(t, tt.code, w.Code)
+			xrequire.JSONEq(t, tt.response, w.Body.String())
+		})
+	}
+
+	t.Run("explicit status code wins", func(t *testing.T) {
+		h := chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+			return "", http.StatusGone, errNotFound
+		}, chai.WithErrors(registry))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		require.Equal(t, http.StatusGone, w.Code)
+	})
+}
+
+type panickingErrorWriter struct{}
+
+func (panickingErrorWriter) WriteError(w http.ResponseWriter, code int, e error) {
+	panic("error writer failed")
+}
+
+type unmarshalableError struct {
+	C chan int `json:"c"`
+}
+
+func (e unmarshalableError) Error() string {
+	return "unmarshalable"
+}
+
+func TestRecover(t *testing.T) {
+	var recovered []string
+	hook := func(r *http.Request, err *chai.PanicError) {
+		recovered = append(recovered, err.Error())
+	}
+
+	panicking := func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+		panic("boom")
+	}
+
+	tcs := []struct {
+		name        string
+		handler     http.Handler
+		code        int
+		contentType string
+		body        string
+		stack       bool
+		recovered   []string
+	}{
+		{
+			name:        "res handler",
+			handler:     chai.NewResHandler(panicking, chai.WithPanicHook(hook)),
+			code:        http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{"error":"Internal Server Error", "status_code":500}`,
+			recovered:   []string{"panic: boom"},
+		},
+		{
+			name: "req res handler",
+			handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+				var m map[string]int
+				m["x"] = 1
+				return "", http.StatusOK, nil
+			}, chai.WithPanicHook(hook)),
+			code:        http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        `{"error":"Internal Server Error", "status_code":500}`,
+			recovered:   []string{"panic: assignment to entry in nil map"},
+		},
+		{
+			name:        "debug mode includes the stack trace",
+			handler:     chai.NewResHandler(panicking, chai.WithDebug(true)),
+			code:        http.StatusInternalServerError,
+			contentType: "application/json",
+			stack:       true,
+		},
+		{
+			name:        "panicking error writer",
+			handler:     chai.NewResHandler(panicking, chai.WithErrorWriter(panickingErrorWriter{})),
+			code:        http.StatusInternalServerError,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Internal Server Error\n",
+		},
+		{
+			name: "error that cannot be marshaled",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+				return "", http.StatusBadRequest, unmarshalableError{}
+			}),
+			code:        http.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"error":"unmarshalable", "status_code":400}`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			recovered = nil
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{}`)))
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.recovered, recovered)
+
+			if tt.stack {
+				var res struct {
+					Error string `json:"error"`
+					Stack string `json:"stack"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, "panic: boom", res.Error)
+				require.Contains(t, res.Stack, "TestRecover")
+				return
+			}
+
+			if tt.contentType == "application/json" {
+				xrequire.JSONEq(t, tt.body, w.Body.String())
+			} else {
+				require.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("http.ErrAbortHandler is re-panicked", func(t *testing.T) {
+		h := chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (string, int, error) {
+			panic(http.ErrAbortHandler)
+		})
+
+		require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+}
+
+func TestDecoding(t *testing.T) {
+	echo := func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*tests.TestRequest, int, error) {
+		return req, http.StatusOK, nil
+	}
+	echoValue := func(req tests.TestRequest, w http.ResponseWriter, r *http.Request) (tests.TestRequest, int, error) {
+		return req, http.StatusOK, nil
+	}
+
+	tcs := []struct {
+		name        string
+		handler     http.Handler
+		contentType string
+		body        string
+		code        int
+		err         string
+	}{
+		{
+			name:    "unknown fields are ignored by default",
+			handler: chai.NewReqResHandler(echo),
+			body:    `{"foob":"f","unknown":1}`,
+			code:    http.StatusOK,
+		},
+		{
+			name:    "unknown fields are rejected in strict mode",
+			handler: chai.NewReqResHandler(echo, chai.WithStrictDecoding()),
+			body:    `{"foob":"f","unknown":1}`,
+			code:    http.StatusBadRequest,
+			err:     `json: unknown field "unknown"`,
+		},
+		{
+			name:        "unknown fields are rejected in strict mode with msgpack",
+			handler:     chai.NewReqResHandler(echo, chai.WithStrictDecoding(), chai.WithCodecs(chai.MsgPack)),
+			contentType: "application/msgpack",
+			body:        "\x82\xa4foob\xa1f\xa7unknown\x01",
+			code:        http.StatusBadRequest,
+			err:         `msgpack: unknown field "unknown"`,
+		},
+		{
+			name:    "trailing data",
+			handler: chai.NewReqResHandler(echo),
+			body:    `{"foob":"f"} {"foob":"g"}`,
+			code:    http.StatusBadRequest,
+			err:     chai.ErrTrailingData.Error(),
+		},
+		{
+			name:    "trailing whitespace",
+			handler: chai.NewReqResHandler(echo),
+			body:    "{\"foob\":\"f\"}\n\t ",
+			code:    http.StatusOK,
+		},
+		{
+			name:    "empty body",
+			handler: chai.NewReqResHandler(echo),
+			body:    "",
+			code:    http.StatusBadRequest,
+			err:     chai.ErrMissingBody.Error(),
+		},
+		{
+			name:    "null body",
+			handler: chai.NewReqResHandler(echo),
+			body:    "null",
+			code:    http.StatusBadRequest,
+			err:     chai.ErrMissingBody.Error(),
+		},
+		{
+			name:    "null body for a value request type",
+			handler: chai.NewReqResHandler(echoValue),
+			body:    "null",
+			code:    http.StatusOK,
+		},
+		{
+			name:    "body over the limit",
+			handler: chai.NewReqResHandler(echo, chai.WithMaxBodyBytes(8)),
+			body:    `{"foob":"ffffffff"}`,
+			code:    http.StatusRequestEntityTooLarge,
+			err:     "http: request body too large",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code, w.Body.String())
+
+			if tt.err != "" {
+				var res struct {
+					Error string `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, tt.err, res.Error)
+			}
+		})
+	}
+}
+
+func TestResponse(t *testing.T) {
+	tcs := []struct {
+		name    string
+		handler http.Handler
+		code    int
+		header  http.Header
+		body    string
+	}{
+		{
+			name: "envelope",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (chai.Response[*tests.TestResponse], int, error) {
+				return chai.Response[*tests.TestResponse]{
+					Status:  http.StatusCreated,
+					Header:  http.Header{"Location": {"/accounts/1"}},
+					Cookies: []*http.Cookie{{Name: "session", Value: "abc"}},
+					Body:    newRes(),
+				}, http.StatusOK, nil
+			}),
+			code: http.StatusCreated,
+			header: http.Header{
+				"Content-Type": {"application/json"},
+				"Location":     {"/accounts/1"},
+				"Set-Cookie":   {"session=abc"},
+			},
+			body: `{"foo":"f","bar":"b","test_inner_response":{"foo_foo":123,"bar_bar":12}}`,
+		},
+		{
+			name: "envelope pointer without a status",
+			handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*chai.Response[string], int, error) {
+				return &chai.Response[string]{Header: http.Header{"X-Foo": {"a", "b"}}, Body: req.Foo}, http.StatusAccepted, nil
+			}),
+			code: http.StatusAccepted,
+			header: http.Header{
+				"Content-Type": {"application/json"},
+				"X-Foo":        {"a", "b"},
+			},
+			body: `"f"`,
+		},
+		{
+			name: "nil envelope pointer",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*chai.Response[string], int, error) {
+				return nil, 0, nil
+			}),
+			code:   http.StatusOK,
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `null`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"foob":"f"}`)))
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.header, w.Header())
+			require.JSONEq(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestFile(t *testing.T) {
+	modTime := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	file := func(w http.ResponseWriter, r *http.Request) (chai.File, int, error) {
+		return chai.File{Name: "report.txt", ModTime: modTime, ETag: `"v1"`, Content: strings.NewReader("0123456789")}, 0, nil
+	}
+
+	tcs := []struct {
+		name    string
+		handler http.Handler
+		header  http.Header
+		code    int
+		resHead map[string]string
+		body    string
+	}{
+		{
+			name:    "file",
+			handler: chai.NewResHandler(file),
+			header:  http.Header{"Accept": {"text/plain"}},
+			code:    http.StatusOK,
+			resHead: map[string]string{
+				"Content-Type":        "text/plain; charset=utf-8",
+				"Content-Disposition": `attachment; filename=report.txt`,
+				"Content-Length":      "10",
+				"Accept-Ranges":       "bytes",
+				"Last-Modified":       "Sun, 02 Jan 2022 03:04:05 GMT",
+				"Etag":                `"v1"`,
+			},
+			body: "0123456789",
+		},
+		{
+			name:    "range",
+			handler: chai.NewResHandler(file),
+			header:  http.Header{"Range": {"bytes=2-4"}},
+			code:    http.StatusPartialContent,
+			resHead: map[string]string{"Content-Range": "bytes 2-4/10", "Content-Length": "3"},
+			body:    "234",
+		},
+		{
+			name:    "unsatisfiable range",
+			handler: chai.NewResHandler(file),
+			header:  http.Header{"Range": {"bytes=20-"}},
+			code:    http.StatusRequestedRangeNotSatisfiable,
+			resHead: map[string]string{"Content-Range": "bytes */10"},
+			body:    "invalid range: failed to overlap\n",
+		},
+		{
+			name:    "if-none-match",
+			handler: chai.NewResHandler(file),
+			header:  http.Header{"If-None-Match": {`"v1"`}},
+			code:    http.StatusNotModified,
+			body:    "",
+		},
+		{
+			name:    "if-modified-since",
+			handler: chai.NewResHandler(file),
+			header:  http.Header{"If-Modified-Since": {"Mon, 03 Jan 2022 00:00:00 GMT"}},
+			code:    http.StatusNotModified,
+			body:    "",
+		},
+		{
+			name: "bytes",
+			handler: chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) ([]byte, int, error) {
+				return []byte{0, 1, 2}, 0, nil
+			}, chai.WithoutBody()),
+			code:    http.StatusOK,
+			resHead: map[string]string{"Content-Type": "application/octet-stream", "Content-Disposition": ""},
+			body:    "\x00\x01\x02",
+		},
+		{
+			name: "inline file with a status code in a response",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*chai.Response[*chai.File], int, error) {
+				return &chai.Response[*chai.File]{
+					Status: http.StatusCreated,
+					Body:   &chai.File{Name: "résumé.pdf", ContentType: "application/pdf", Inline: true, Content: strings.NewReader("%PDF")},
+				}, 0, nil
+			}),
+			header: http.Header{"Range": {"bytes=0-1"}},
+			code:   http.StatusCreated,
+			resHead: map[string]string{
+				"Content-Type":        "application/pdf",
+				"Content-Disposition": `inline; filename*=utf-8''r%C3%A9sum%C3%A9.pdf`,
+			},
+			body: "%PDF",
+		},
+		{
+			name: "read seeker",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*bytes.Reader, int, error) {
+				return bytes.NewReader([]byte("abc")), 0, nil
+			}),
+			header:  http.Header{"Range": {"bytes=1-"}},
+			code:    http.StatusPartialContent,
+			resHead: map[string]string{"Content-Type": "application/octet-stream"},
+			body:    "bc",
+		},
+		{
+			name: "nil file",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*chai.File, int, error) {
+				return nil, 0, nil
+			}),
+			code:    http.StatusNoContent,
+			resHead: map[string]string{"Content-Type": ""},
+			body:    "",
+		},
+		{
+			name: "nil response of a file",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*chai.Response[*chai.File], int, error) {
+				return nil, 0, nil
+			}),
+			code:    http.StatusNoContent,
+			resHead: map[string]string{"Content-Type": ""},
+			body:    "",
+		},
+		{
+			name: "nil file in a response",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*chai.Response[*chai.File], int, error) {
+				return &chai.Response[*chai.File]{Body: nil}, 0, nil
+			}),
+			code:    http.StatusNoContent,
+			resHead: map[string]string{"Content-Type": ""},
+			body:    "",
+		},
+		{
+			name: "nil reader with a status code in a response",
+			handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*chai.Response[*bytes.Reader], int, error) {
+				return &chai.Response[*bytes.Reader]{Status: http.StatusAccepted}, 0, nil
+			}),
+			code: http.StatusAccepted,
+			body: "",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header = tt.header
+			if r.Header == nil {
+				r.Header = http.Header{}
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code)
+			for name, value := range tt.resHead {
+				require.Equal(t, value, w.Header().Get(name), name)
+			}
+			require.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	secret := []byte("s3cr3t")
+	wr := chai.NewWebhookRegistry()
+	created := chai.RegisterWebhook[*tests.TestEvent](wr, "event.created", "Sent when an event is created")
+
+	require.Equal(t, []chai.WebhookEvent{created.Event()}, wr.Events())
+	require.Equal(t, "event.created", created.Event().Name)
+	require.True(t, strings.HasSuffix(created.Event().File, "chai_test.go"))
+
+	tcs := []struct {
+		name      string
+		responses []int
+		attempts  int
+		status    chai.DeliveryStatus
+		err       error
+	}{
+		{
+			name:      "delivered",
+			responses: []int{http.StatusNoContent},
+			attempts:  1,
+			status:    chai.DeliverySucceeded,
+		},
+		{
+			name:      "retried",
+			responses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			attempts:  3,
+			status:    chai.DeliverySucceeded,
+		},
+		{
+			name:      "not retried",
+			responses: []int{http.StatusBadRequest},
+			attempts:  1,
+			status:    chai.DeliveryFailed,
+			err:       &chai.WebhookStatusError{StatusCode: http.StatusBadRequest},
+		},
+		{
+			name:      "out of attempts",
+			responses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK},
+			attempts:  3,
+			status:    chai.DeliveryFailed,
+			err:       &chai.WebhookStatusError{StatusCode: http.StatusInternalServerError},
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []*tests.TestEvent
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := chai.VerifyWebhook(secret, r, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, "event.created", r.Header.Get(chai.WebhookEventHeader))
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				_, err = chai.VerifyWebhook([]byte("wrong"), httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)), 0)
+				require.ErrorIs(t, err, chai.ErrInvalidSignature)
+
+				ev := new(tests.TestEvent)
+				require.NoError(t, json.Unmarshal(body, ev))
+				received = append(received, ev)
+
+				w.WriteHeader(tt.responses[len(received)-1])
+			}))
+			defer srv.Close()
+
+			store := chai.NewMemoryDeliveryStore()
+			sender := chai.NewWebhookSender(secret,
+				chai.WithMaxAttempts(3),
+				chai.WithBackoff(func(attempt int) time.Duration { return time.Millisecond }),
+				chai.WithDeliveryStore(store),
+			)
+
+			d, err := created.Send(context.Background(), sender, srv.URL, &tests.TestEvent{ID: 1, Message: "a"})
+			if tt.err != nil {
+				require.Equal(t, tt.err, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, received, tt.attempts)
+			require.Equal(t, &tests.TestEvent{ID: 1, Message: "a"}, received[0])
+
+			require.Equal(t, tt.attempts, d.Attempts)
+			require.Equal(t, tt.status, d.Status)
+			require.Equal(t, tt.responses[tt.attempts-1], d.StatusCode)
+
+			stored, ok := store.Get(d.ID)
+			require.True(t, ok)
+			require.Equal(t, *d, stored)
+			require.Len(t, store.List(), 1)
+		})
+	}
+
+	t.Run("canceled during backoff", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		d, err := created.Send(ctx, chai.NewWebhookSender(secret), srv.URL, &tests.TestEvent{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, chai.DeliveryFailed, d.Status)
+		require.Equal(t, 1, d.Attempts)
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		backoff := chai.ExponentialBackoff(time.Second, 5*time.Second)
+
+		require.Equal(t, time.Second, backoff(1))
+		require.Equal(t, 2*time.Second, backoff(2))
+		require.Equal(t, 4*time.Second, backoff(3))
+		require.Equal(t, 5*time.Second, backoff(4))
+		require.Equal(t, 5*time.Second, backoff(10))
+	})
+}
+
+func TestSSE(t *testing.T) {
+	tcs := []struct {
+		name        string
+		handler     http.Handler
+		header      http.Header
+		code        int
+		contentType string
+		body        string
+	}{
+		{
+			name: "events",
+			handler: chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[*tests.TestEvent], int, error) {
+				events := make(chan chai.Event[*tests.TestEvent], 3)
+				events <- chai.Event[*tests.TestEvent]{Data: &tests.TestEvent{ID: 1, Message: "a"}}
+				events <- chai.Event[*tests.TestEvent]{Name: "update", ID: "2", Retry: 3 * time.Second, Data: &tests.TestEvent{ID: 2, Message: "b"}}
+				events <- chai.Event[*tests.TestEvent]{Name: "evil\nevent: x", Data: nil}
+				close(events)
+
+				return events, 0, nil
+			}),
+			code:        http.StatusOK,
+			contentType: "text/event-stream",
+			body: "data: {\"id\":1,\"message\":\"a\"}\n\n" +
+				"id: 2\nevent: update\nretry: 3000\ndata: {\"id\":2,\"message\":\"b\"}\n\n" +
+				"event: evilevent: x\ndata: null\n\n",
+		},
+		{
+			name: "multi-line string data and Last-Event-ID",
+			handler: chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[string], int, error) {
+				events := make(chan chai.Event[string], 1)
+				events <- chai.Event[string]{ID: "8", Data: "resumed after " + req.LastEventID + "\nsecond line"}
+				close(events)
+
+				return events, 0, nil
+			}),
+			header:      http.Header{"Last-Event-Id": {"7"}},
+			code:        http.StatusOK,
+			contentType: "text/event-stream",
+			body:        "id: 8\ndata: resumed after 7\ndata: second line\n\n",
+		},
+		{
+			name: "error before the stream starts",
+			handler: chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[string], int, error) {
+				return nil, http.StatusNotFound, errors.New("no such topic")
+			}),
+			code:        http.StatusNotFound,
+			contentType: "application/json",
+			body:        `{"error":"no such topic","status_code":404}`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.header {
+				r.Header[k] = v
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.body, w.Body.String())
+		})
+	}
+
+	t.Run("stops when the client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+
+		h := chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[int], int, error) {
+			events := make(chan chai.Event[int])
+
+			go func() {
+				defer close(stopped)
+				for i := 0; ; i++ {
+					if i == 2 {
+						cancel()
+					}
+					select {
+					case events <- chai.Event[int]{Data: i}:
+					case <-r.Context().Done():
+						return
+					}
+				}
+			}()
+
+			return events, 0, nil
+		})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+		<-stopped
+		require.True(t, strings.HasPrefix(w.Body.String(), "data: 0\n\ndata: 1\n\n"))
+	})
+}
+
+func TestWebSocket(t *testing.T) {
+	hookErrs := make(chan error, 1)
+	hookCodes := make(chan int, 1)
+
+	echo := func(req *tests.TestEventsRequest, conn *chai.WebSocketConn[*tests.TestEvent, *tests.TestEvent], r *http.Request) error {
+		for {
+			msg, err := conn.Receive()
+			if errors.Is(err, chai.ErrInvalidMessage) {
+				if err := conn.Send(&tests.TestEvent{ID: -1, Message: "invalid"}); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if msg.Message == "stop" {
+				return errors.New("stopped")
+			}
+
+			if err := conn.Send(&tests.TestEvent{ID: msg.ID + 1, Message: strings.ToUpper(msg.Message) + req.LastEventID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	h := chai.NewWebSocketHandler(echo, chai.WithPingInterval(10*time.Millisecond), chai.WithErrorHook(func(r *http.Request, code int, err error) {
+		hookCodes <- code
+		hookErrs <- err
+	}))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	t.Run("messages", func(t *testing.T) {
+		cfg, err := websocket.NewConfig(wsURL, srv.URL)
+		require.NoError(t, err)
+		cfg.Header = http.Header{"Last-Event-Id": {"!"}}
+
+		ws, err := websocket.DialConfig(cfg)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		var res tests.TestEvent
+
+		require.NoError(t, websocket.JSON.Send(ws, &tests.TestEvent{ID: 1, Message: "a"}))
+		require.NoError(t, websocket.JSON.Receive(ws, &res))
+		require.Equal(t, tests.TestEvent{ID: 2, Message: "A!"}, res)
+
+		require.NoError(t, websocket.Message.Send(ws, "{"))
+		require.NoError(t, websocket.JSON.Receive(ws, &res))
+		require.Equal(t, tests.TestEvent{ID: -1, Message: "invalid"}, res)
+
+		// Wait for a few pings, which the client answers while it waits for the reply.
+		time.Sleep(50 * time.Millisecond)
+
+		require.NoError(t, websocket.JSON.Send(ws, &tests.TestEvent{ID: 5, Message: "b"}))
+		require.NoError(t, websocket.JSON.Receive(ws, &res))
+		require.Equal(t, tests.TestEvent{ID: 6, Message: "B!"}, res)
+
+		require.NoError(t, websocket.JSON.Send(ws, &tests.TestEvent{Message: "stop"}))
+		require.Equal(t, http.StatusInternalServerError, <-hookCodes)
+		require.EqualError(t, <-hookErrs, "stopped")
+
+		require.Error(t, websocket.JSON.Receive(ws, &res))
+	})
+
+	t.Run("client close", func(t *testing.T) {
+		ws, err := websocket.Dial(wsURL, "", srv.URL)
+		require.NoError(t, err)
+		require.NoError(t, ws.Close())
+
+		require.Equal(t, http.StatusInternalServerError, <-hookCodes)
+		require.ErrorIs(t, <-hookErrs, io.EOF)
+	})
+
+	tcs := []struct {
+		name    string
+		handler http.Handler
+		header  http.Header
+		code    int
+		body    string
+	}{
+		{
+			name:    "not a handshake",
+			handler: chai.NewWebSocketHandler(echo),
+			code:    http.StatusUpgradeRequired,
+			body:    `{"error":"the request is not a WebSocket handshake","status_code":426}`,
+		},
+		{
+			name:    "cross-origin",
+			handler: chai.NewWebSocketHandler(echo),
+			header:  http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}, "Origin": {"http://evil.example.com"}},
+			code:    http.StatusForbidden,
+			body:    `{"error":"the origin of the request is not allowed","status_code":403}`,
+		},
+		{
+			name: "invalid request",
+			handler: chai.NewWebSocketHandler(func(req *tests.TestValidatedRequest, conn *chai.WebSocketConn[string, string], r *http.Request) error {
+				return nil
+			}),
+			header: http.Header{"Connection": {"keep-alive, Upgrade"}, "Upgrade": {"websocket"}},
+			code:   http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/?limit=5", nil)
+			for k, v := range tt.header {
+				r.Header[k] = v
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.body != "" {
+				require.JSONEq(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStream(t *testing.T) {
+	items := func(n int) chai.StreamHandlerFunc[*tests.TestEventsRequest, *tests.TestEvent, error] {
+		return func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan *tests.TestEvent, int, error) {
+			ch := make(chan *tests.TestEvent)
+			go func() {
+				defer close(ch)
+				for i := 1; i <= n; i++ {
+					select {
+					case ch <- &tests.TestEvent{ID: i, Message: req.Topic}:
+					case <-r.Context().Done():
+						return
+					}
+				}
+			}()
+			return ch, 0, nil
+		}
+	}
+
+	tcs := []struct {
+		name        string
+		handler     http.Handler
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "ndjson by default",
+			handler:     chai.NewStreamHandler(items(2), chai.WithoutBody()),
+			code:        http.StatusOK,
+			contentType: "application/x-ndjson",
+			body:        "{\"id\":1,\"message\":\"\"}\n{\"id\":2,\"message\":\"\"}\n",
+		},
+		{
+			name:        "json array",
+			handler:     chai.NewStreamHandler(items(2), chai.WithoutBody()),
+			accept:      "application/json",
+			code:        http.StatusOK,
+			contentType: "application/json",
+			body:        "[{\"id\":1,\"message\":\"\"},{\"id\":2,\"message\":\"\"}]\n",
+		},
+		{
+			name:        "empty json array",
+			handler:     chai.NewStreamHandler(items(0), chai.WithoutBody()),
+			accept:      "application/json",
+			code:        http.StatusOK,
+			contentType: "application/json",
+			body:        "[]\n",
+		},
+		{
+			name:        "not acceptable",
+			handler:     chai.NewStreamHandler(items(2), chai.WithoutBody()),
+			accept:      "application/xml",
+			code:        http.StatusNotAcceptable,
+			contentType: "application/json",
+			body:        `{"error":"not acceptable: application/xml","status_code":406}`,
+		},
+		{
+			name: "error before the stream starts",
+			handler: chai.NewStreamHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan *tests.TestEvent, int, error) {
+				return nil, http.StatusForbidden, errors.New("forbidden")
+			}, chai.WithoutBody()),
+			code:        http.StatusForbidden,
+			contentType: "application/json",
+			body:        `{"error":"forbidden","status_code":403}`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestSSEMarshalError(t *testing.T) {
+	var hookCodes []int
+
+	h := chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[float64], int, error) {
+		events := make(chan chai.Event[float64], 3)
+		events <- chai.Event[float64]{Data: 1.5}
+		events <- chai.Event[float64]{Data: math.NaN()}
+		events <- chai.Event[float64]{Data: 2.5}
+		close(events)
+
+		return events, 0, nil
+	}, chai.WithErrorHook(func(r *http.Request, code int, err error) {
+		hookCodes = append(hookCodes, code)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "data: 1.5\n\n", w.Body.String())
+	require.Equal(t, []int{http.StatusInternalServerError}, hookCodes)
+}
+
+func TestStreamMarshalError(t *testing.T) {
+	var hookCodes []int
+	done := make(chan struct{})
+
+	h := chai.NewStreamHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan float64, int, error) {
+		ch := make(chan float64)
+		go func() {
+			defer close(done)
+			for _, v := range []float64{1.5, math.NaN(), 2.5} {
+				select {
+				case ch <- v:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}()
+		return ch, 0, nil
+	}, chai.WithoutBody(), chai.WithErrorHook(func(r *http.Request, code int, err error) {
+		hookCodes = append(hookCodes, code)
+	}))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	r, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	r.Header.Set("Accept", "application/json")
+
+	res, err := http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// The response is aborted, so the client can tell that the stream is incomplete.
+	b, err := io.ReadAll(res.Body)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, "[1.5", string(b))
+	require.Equal(t, []int{http.StatusInternalServerError}, hookCodes)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the producer is still sending")
+	}
+}
+
+type formPart struct {
+	name     string
+	filename string
+	content  string
+}
+
+func multipartBody(t *testing.T, parts ...formPart) (io.Reader, string) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	for _, p := range parts {
+		var w io.Writer
+		var err error
+		if p.filename != "" {
+			w, err = mw.CreateFormFile(p.name, p.filename)
+		} else {
+			w, err = mw.CreateFormField(p.name)
+		}
+		require.NoError(t, err)
+		_, err = io.WriteString(w, p.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	return &buf, mw.FormDataContentType()
+}
+
+func TestMultipart(t *testing.T) {
+	upload := func(req *tests.TestUploadRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		f, err := req.Image.Open()
+		if err != nil {
+			return "", http.StatusInternalServerError, err
+		}
+		defer f.Close()
+		b, err := io.ReadAll(f)
+		if err != nil {
+			return "", http.StatusInternalServerError, err
+		}
+
+		names := make([]string, 0)
+		for _, a := range req.Attachments {
+			names = append(names, a.Filename)
+		}
+
+		return fmt.Sprintf("%s %v %s=%s %v", req.Caption, req.Tags, req.Image.Filename, b, names), http.StatusOK, nil
+	}
+
+	streamingUpload := func(req *tests.TestStreamingUploadRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		res := req.Caption
+		for {
+			part, err := req.Files.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", http.StatusBadRequest, err
+			}
+
+			b, err := io.ReadAll(part)
+			if errors.Is(err, chai.ErrFileTooLarge) {
+				return "", http.StatusRequestEntityTooLarge, err
+			}
+			if err != nil {
+				return "", http.StatusBadRequest, err
+			}
+			res += fmt.Sprintf(" %s:%s=%s", part.FormName(), part.FileName(), b)
+		}
+
+		return res, http.StatusOK, nil
+	}
+
+	limitedStreamingUpload := func(req *tests.TestLimitedStreamingUploadRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+		return streamingUpload(&tests.TestStreamingUploadRequest{Caption: req.Caption, Files: req.Files}, w, r)
+	}
+
+	tcs := []struct {
+		name        string
+		handler     http.Handler
+		parts       []formPart
+		contentType string
+		code        int
+		response    string
+	}{
+		{
+			name:    "fields and files",
+			handler: chai.NewReqResHandler(upload, chai.WithMultipart()),
+			parts: []formPart{
+				{name: "caption", content: "hello"},
+				{name: "tags", content: "a"},
+				{name: "image", filename: "a.png", content: "12345"},
+				{name: "tags", content: "b"},
+				{name: "attachments", filename: "x.txt", content: "x"},
+				{name: "attachments", filename: "y.txt", content: "y"},
+			},
+			code:     http.StatusOK,
+			response: `"hello [a b] a.png=12345 [x.txt y.txt]"`,
+		},
+		{
+			name:    "file larger than its maxSize tag",
+			handler: chai.NewReqResHandler(upload, chai.WithMultipart()),
+			parts: []formPart{
+				{name: "image", filename: "a.png", content: "123456789"},
+			},
+			code:     http.StatusRequestEntityTooLarge,
+			response: `{"error":"file is too large: a.png is larger than 8 bytes","status_code":413}`,
+		},
+		{
+			name:    "file larger than the handler's limit",
+			handler: chai.NewReqResHandler(upload, chai.WithMultipart(), chai.WithMaxFileBytes(2)),
+			parts: []formPart{
+				{name: "image", filename: "a.png", content: "1234"},
+				{name: "attachments", filename: "x.txt", content: "xyz"},
+			},
+			code:     http.StatusRequestEntityTooLarge,
+			response: `{"error":"file is too large: x.txt is larger than 2 bytes","status_code":413}`,
+		},
+		{
+			name:    "missing required file",
+			handler: chai.NewReqResHandler(upload, chai.WithMultipart()),
+			parts: []formPart{
+				{name: "caption", content: "hello"},
+			},
+			code:     http.StatusUnprocessableEntity,
+			response: `{"error":"validation failed: image is required","status_code":422,"errors":[{"field":"image","rule":"required","message":"is required"}]}`,
+		},
+		{
+			name:        "not multipart",
+			handler:     chai.NewReqResHandler(upload, chai.WithMultipart()),
+			contentType: "application/json",
+			code:        http.StatusUnsupportedMediaType,
+			response:    `{"error":"unsupported media type: expected multipart/form-data","status_code":415}`,
+		},
+		{
+			name:    "streaming",
+			handler: chai.NewReqResHandler(streamingUpload, chai.WithMultipart()),
+			parts: []formPart{
+				{name: "caption", content: "hello"},
+				{name: "files", filename: "a.txt", content: "aaa"},
+				{name: "files", filename: "b.txt", content: "bbb"},
+			},
+			code:     http.StatusOK,
+			response: `"hello files:a.txt=aaa files:b.txt=bbb"`,
+		},
+		{
+			name:    "streaming file larger than the handler's limit",
+			handler: chai.NewReqResHandler(streamingUpload, chai.WithMultipart(), chai.WithMaxFileBytes(2)),
+			parts: []formPart{
+				{name: "files", filename: "a.txt", content: "aaa"},
+			},
+			code:     http.StatusRequestEntityTooLarge,
+			response: `{"error":"file is too large","status_code":413}`,
+		},
+		{
+			name:    "streaming file larger than the field's limit",
+			handler: chai.NewReqResHandler(limitedStreamingUpload, chai.WithMultipart()),
+			parts: []formPart{
+				{name: "files", filename: "a.txt", content: "aaa"},
+			},
+			code:     http.StatusRequestEntityTooLarge,
+			response: `{"error":"file is too large","status_code":413}`,
+		},
+		{
+			name:    "streaming file within the field's limit",
+			handler: chai.NewReqResHandler(limitedStreamingUpload, chai.WithMultipart(), chai.WithMaxFileBytes(1)),
+			parts: []formPart{
+				{name: "files", filename: "a.txt", content: "aa"},
+			},
+			code:     http.StatusOK,
+			response: `" files:a.txt=aa"`,
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartBody(t, tt.parts...)
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/", body)
+			r.Header.Set("Content-Type", contentType)
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.code, w.Code, w.Body.String())
+			xrequire.JSONEq(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestMiddlewareDocs(t *testing.T) {
+	docs := chai.MiddlewareDocs{
+		Headers:   []chai.MiddlewareHeader{{Name: "Authorization", Required: true}},
+		Responses: map[int]string{http.StatusUnauthorized: "Unauthorized"},
+	}
+
+	auth := chai.DocumentMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, docs)
+
+	tcs := []struct {
+		name     string
+		mw       func(http.Handler) http.Handler
+		wantDocs chai.MiddlewareDocs
+		wantOK   bool
+	}{
+		{name: "documented", mw: auth, wantDocs: docs, wantOK: true},
+		{name: "undocumented", mw: func(next http.Handler) http.Handler { return next }},
+		{name: "panicking", mw: func(next http.Handler) http.Handler { panic("not in a router") }},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := chai.MiddlewareDocsOf(tt.mw)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantDocs, got)
+		})
+	}
+
+	h := auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "key")
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDoc(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	tcs := []struct {
+		name         string
+		opts         []chai.Option
+		wantReq      any
+		wantRes      any
+		wantErr      any
+		undocumented bool
+	}{
+		{name: "untyped"},
+		{name: "request", opts: []chai.Option{chai.WithRequestType((*tests.TestRequest)(nil))}, wantReq: (*tests.TestRequest)(nil)},
+		{name: "response", opts: []chai.Option{chai.WithResponseType((*tests.TestResponse)(nil))}, wantRes: (*tests.TestResponse)(nil), wantErr: (*error)(nil)},
+		{
+			name:    "request and response",
+			opts:    []chai.Option{chai.WithRequestType((*tests.TestRequest)(nil)), chai.WithResponseType((*tests.TestResponse)(nil)), chai.WithErrorType((*tests.TestError)(nil))},
+			wantReq: (*tests.TestRequest)(nil),
+			wantRes: (*tests.TestResponse)(nil),
+			wantErr: (*tests.TestError)(nil),
+		},
+		{name: "undocumented", opts: []chai.Option{chai.WithoutDocs()}, undocumented: true},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			d := chai.Doc(h, tt.opts...)
+			require.Equal(t, tt.undocumented, d.Undocumented())
+
+			reqer, ok := d.(chai.Reqer)
+			require.Equal(t, tt.wantReq != nil, ok)
+			if ok {
+				require.Equal(t, tt.wantReq, reqer.Req())
+			}
+
+			reser, ok := d.(chai.ResErrer)
+			require.Equal(t, tt.wantRes != nil, ok)
+			if ok {
+				require.Equal(t, tt.wantRes, reser.Res())
+				require.Equal(t, tt.wantErr, reser.Err())
+			}
+
+			w := httptest.NewRecorder()
+			d.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, http.StatusTeapot, w.Code)
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package chai
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrNotAcceptable        = errors.New("not acceptable")
+	ErrMissingBody          = errors.New("request body is missing")
+	ErrTrailingData         = errors.New("request body has data after the first value")
+)
+
+// Codec decodes request bodies and encodes response bodies of a single media type.
+type Codec interface {
+	ContentType() string
+	Decode(r io.Reader, v any) error
+	Encode(w io.Writer, v any) error
+}
+
+// StrictCodec is implemented by codecs that can reject bodies with fields the decoded type does not have.
+type StrictCodec interface {
+	Codec
+	DecodeStrict(r io.Reader, v any) error
+}
+
+// CodecRegistry holds the codecs a handler can use.
+// Request bodies are decoded with the codec matching their Content-Type header
+// and responses are encoded with the best match for the Accept header.
+// The first registered codec is used when either header is missing.
+type CodecRegistry struct {
+	codecs []Codec
+}
+
+func NewCodecRegistry(codecs ...Codec) *CodecRegistry {
+	r := &CodecRegistry{}
+
+	for _, c := range codecs {
+		r.Register(c)
+	}
+
+	return r
+}
+
+// Register adds c to the registry, replacing any codec with the same content type.
+func (cr *CodecRegistry) Register(c Codec) {
+	for i := range cr.codecs {
+		if cr.codecs[i].ContentType() == c.ContentType() {
+			cr.codecs[i] = c
+			return
+		}
+	}
+
+	cr.codecs = append(cr.codecs, c)
+}
+
+func (cr *CodecRegistry) ContentTypes() []string {
+	res := make([]string, len(cr.codecs))
+
+	for i, c := range cr.codecs {
+		res[i] = c.ContentType()
+	}
+
+	return res
+}
+
+// ForContentType returns the codec that decodes the given Content-Type header value.
+func (cr *CodecRegistry) ForContentType(contentType string) (Codec, error) {
+	if len(cr.codecs) == 0 {
+		return nil, fmt.Errorf("%w: no codecs registered", ErrUnsupportedMediaType)
+	}
+
+	if contentType == "" {
+		return cr.codecs[0], nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, err)
+	}
+
+	for _, c := range cr.codecs {
+		if c.ContentType() == mediaType {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+}
+
+// ForAccept returns the codec that best matches the given Accept header value.
+func (cr *CodecRegistry) ForAccept(accept string) (Codec, error) {
+	if len(cr.codecs) == 0 {
+		return nil, fmt.Errorf("%w: no codecs registered", ErrNotAcceptable)
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return cr.codecs[0], nil
+	}
+
+	i, err := negotiate(accept, cr.ContentTypes())
+	if err != nil {
+		return nil, err
+	}
+
+	return cr.codecs[i], nil
+}
+
+// negotiate returns the index of the media type of offers that best matches the given Accept header value.
+func negotiate(accept string, offers []string) (int, error) {
+	if strings.TrimSpace(accept) == "" && len(offers) > 0 {
+		return 0, nil
+	}
+
+	for _, ar := range parseAccept(accept) {
+		for i, offer := range offers {
+			if ar.matches(offer) {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrNotAcceptable, accept)
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+func (ar acceptRange) matches(contentType string) bool {
+	if ar.mediaType == "*/*" || ar.mediaType == contentType {
+		return true
+	}
+
+	return strings.HasSuffix(ar.mediaType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(ar.mediaType, "*"))
+}
+
+// parseAccept returns the media ranges of an Accept header sorted by descending quality, dropping those with q=0.
+func parseAccept(accept string) []acceptRange {
+	res := make([]acceptRange, 0)
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(s, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		res = append(res, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].q > res[j].q })
+
+	return res
+}
+
+var (
+	JSON    Codec = jsonCodec{}
+	XML     Codec = xmlCodec{}
+	Form    Codec = formCodec{}
+	MsgPack Codec = msgPackCodec{}
+	Text    Codec = textCodec{}
+)
+
+// DefaultCodecs is used by handlers that are not configured with their own codecs.
+var DefaultCodecs = NewCodecRegistry(JSON)
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return decodeJSON(json.NewDecoder(r), v)
+}
+
+func (jsonCodec) DecodeStrict(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	return decodeJSON(dec, v)
+}
+
+// decodeJSON decodes a single JSON value and rejects anything but whitespace after it.
+func decodeJSON(dec *json.Decoder, v any) error {
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return ErrTrailingData
+	}
+
+	return nil
+}
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) ContentType() string {
+	return "application/xml"
+}
+
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+type msgPackCodec struct{}
+
+func (msgPackCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgPackCodec) Decode(r io.Reader, v any) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+func (msgPackCodec) DecodeStrict(r io.Reader, v any) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(true)
+
+	return dec.Decode(v)
+}
+
+func (msgPackCodec) Encode(w io.Writer, v any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+
+	return enc.Encode(v)
+}
+
+type textCodec struct{}
+
+func (textCodec) ContentType() string {
+	return "text/plain"
+}
+
+func (textCodec) Decode(r io.Reader, v any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			if !rv.CanSet() {
+				return fmt.Errorf("text: cannot decode into nil %s", rv.Type())
+			}
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		if tu, ok := rv.Interface().(encoding.TextUnmarshaler); ok {
+			return tu.UnmarshalText(b)
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.String:
+		rv.SetString(string(b))
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		rv.SetBytes(b)
+	default:
+		return setValue(rv, strings.TrimSpace(string(b)))
+	}
+
+	return nil
+}
+
+func (textCodec) Encode(w io.Writer, v any) error {
+	var err error
+
+	switch v := v.(type) {
+	case string:
+		_, err = io.WriteString(w, v)
+	case []byte:
+		_, err = w.Write(v)
+	case encoding.TextMarshaler:
+		var b []byte
+		b, err = v.MarshalText()
+		if err == nil {
+			_, err = w.Write(b)
+		}
+	default:
+		_, err = fmt.Fprint(w, v)
+	}
+
+	return err
+}
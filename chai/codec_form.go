@@ -0,0 +1,199 @@
+package chai
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+type formCodec struct{}
+
+func (formCodec) ContentType() string {
+	return "application/x-www-form-urlencoded"
+}
+
+// Decode fills the fields of a struct from the form values named by their `form` tag, or their `json` tag when there is none.
+// Maps with string keys are also supported.
+func (formCodec) Decode(r io.Reader, v any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			if !rv.CanSet() {
+				return fmt.Errorf("form: cannot decode into nil %s", rv.Type())
+			}
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return decodeFormStruct(rv, values)
+	case reflect.Map:
+		return decodeFormMap(rv, values)
+	default:
+		return fmt.Errorf("form: cannot decode into %s", rv.Type())
+	}
+}
+
+func decodeFormStruct(v reflect.Value, values url.Values) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct {
+			fv, err := fieldByIndex(v, []int{i})
+			if err != nil {
+				return err
+			}
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := decodeFormStruct(fv, values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := formFieldName(f)
+		if name == "" || len(values[name]) == 0 {
+			continue
+		}
+
+		if err := setValues(v.Field(i), values[name]); err != nil {
+			return fmt.Errorf("form: field %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func decodeFormMap(v reflect.Value, values url.Values) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("form: cannot decode into %s", v.Type())
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	for k, vs := range values {
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err := setValues(ev, vs); err != nil {
+			return fmt.Errorf("form: field %q: %w", k, err)
+		}
+		v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), ev)
+	}
+
+	return nil
+}
+
+func (formCodec) Encode(w io.Writer, v any) error {
+	values := url.Values{}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			_, err := io.WriteString(w, "")
+			return err
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		encodeFormStruct(rv, values)
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			addFormValues(values, fmt.Sprint(iter.Key().Interface()), iter.Value())
+		}
+	default:
+		return fmt.Errorf("form: cannot encode %s", rv.Type())
+	}
+
+	_, err := io.WriteString(w, values.Encode())
+
+	return err
+}
+
+func encodeFormStruct(v reflect.Value, values url.Values) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct {
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				encodeFormStruct(fv, values)
+			}
+			continue
+		}
+
+		name := formFieldName(f)
+		if name == "" {
+			continue
+		}
+
+		addFormValues(values, name, fv)
+	}
+}
+
+func addFormValues(values url.Values, name string, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			addFormValues(values, name, v.Index(i))
+		}
+		return
+	}
+
+	values.Add(name, fmt.Sprint(v.Interface()))
+}
+
+func formFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+
+	for _, tag := range []string{"form", "json"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return f.Name
+}
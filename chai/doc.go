@@ -0,0 +1,184 @@
+package chai
+
+import (
+	"net/http"
+)
+
+// DocHandler is implemented by the handlers returned by Doc.
+type DocHandler interface {
+	http.Handler
+	// Unwrap returns the documented handler.
+	Unwrap() http.Handler
+	// Undocumented reports whether the handler is excluded from the documents, see WithoutDocs.
+	Undocumented() bool
+}
+
+// Doc wraps a handler that is not a typed chai handler, e.g. a plain http.HandlerFunc, a file server or a third-party handler,
+// so that the types of its requests, responses and errors, set with WithRequestType, WithResponseType and WithErrorType, are documented
+// like those of the typed handlers. The options that document the typed handlers, e.g. WithErrors, WithCodecs or WithResponseHeader,
+// apply too, and WithoutDocs excludes the handler from the documents. The requests are served by h as is.
+//
+// The swaggo annotations of h are parsed if it is a function, and its types are documented by reflection otherwise.
+func Doc(h http.Handler, opts ...Option) DocHandler {
+	d := &docHandler{h: h, opts: newOptions(opts)}
+
+	switch {
+	case d.opts.docReq != nil && d.opts.docRes != nil:
+		return &docReqResHandler{docHandler: d, docReq: docReq{d.opts}, docRes: docRes{d.opts}}
+	case d.opts.docReq != nil:
+		return &docReqHandler{docHandler: d, docReq: docReq{d.opts}}
+	case d.opts.docRes != nil:
+		return &docResHandler{docHandler: d, docRes: docRes{d.opts}}
+	default:
+		return d
+	}
+}
+
+// WithRequestType documents the requests of a handler wrapped with Doc as values of the type v points to, e.g. (*model.AddAccount)(nil).
+// The fields tagged with `path`, `query`, `header` or `cookie` are documented as parameters, and the others as the body, unless WithoutBody is set.
+func WithRequestType(v any) Option {
+	return func(o *options) {
+		o.docReq = v
+	}
+}
+
+// WithResponseType documents the successful responses of a handler wrapped with Doc as values of the type v points to, e.g. (*model.Account)(nil).
+func WithResponseType(v any) Option {
+	return func(o *options) {
+		o.docRes = v
+	}
+}
+
+// WithErrorType documents the error responses of a handler wrapped with Doc, which has a response type, as values of the type v points to.
+// They are documented as strings by default.
+func WithErrorType(v any) Option {
+	return func(o *options) {
+		o.docErr = v
+	}
+}
+
+// WithoutDocs excludes a handler wrapped with Doc from the documents.
+func WithoutDocs() Option {
+	return func(o *options) {
+		o.undocumented = true
+	}
+}
+
+type docHandler struct {
+	h    http.Handler
+	opts *options
+}
+
+func (d *docHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.h.ServeHTTP(w, r)
+}
+
+func (d *docHandler) Unwrap() http.Handler {
+	return d.h
+}
+
+func (d *docHandler) Undocumented() bool {
+	return d.opts.undocumented
+}
+
+// Handler returns the function of the wrapped handler, whose annotations are parsed, or the wrapped handler if it is not a function.
+func (d *docHandler) Handler() any {
+	switch h := d.h.(type) {
+	case http.HandlerFunc:
+		return (func(http.ResponseWriter, *http.Request))(h)
+	case Handlerer:
+		return h.Handler()
+	default:
+		return h
+	}
+}
+
+func (d *docHandler) ContentTypes() []string {
+	return d.opts.codecs.ContentTypes()
+}
+
+// BinaryContentTypes returns the media types of the handler's binary responses.
+func (d *docHandler) BinaryContentTypes() []string {
+	if len(d.opts.binaryContentTypes) == 0 {
+		return binaryContentTypes
+	}
+
+	return d.opts.binaryContentTypes
+}
+
+func (d *docHandler) ErrorWriter() ErrorWriter {
+	return d.opts.errorWriter
+}
+
+func (d *docHandler) ErrorRegistry() *ErrorRegistry {
+	return d.opts.errors
+}
+
+func (d *docHandler) Callbacks() []Callback {
+	return d.opts.callbacks
+}
+
+func (d *docHandler) Links() []Link {
+	return d.opts.links
+}
+
+func (d *docHandler) ResponseHeaders() []ResponseHeader {
+	return d.opts.responseHeaders
+}
+
+type docReq struct {
+	opts *options
+}
+
+func (d docReq) Req() any {
+	return d.opts.docReq
+}
+
+func (d docReq) DecodesBody() bool {
+	return !d.opts.noBody
+}
+
+func (d docReq) DecodesStrictly() bool {
+	return d.opts.strict
+}
+
+// RequestContentTypes returns the media types of the request bodies the handler decodes.
+func (d docReq) RequestContentTypes() []string {
+	if d.opts.multipart {
+		return []string{MultipartContentType}
+	}
+
+	return d.opts.codecs.ContentTypes()
+}
+
+type docRes struct {
+	opts *options
+}
+
+func (d docRes) Res() any {
+	return d.opts.docRes
+}
+
+func (d docRes) Err() any {
+	if d.opts.docErr == nil {
+		return (*error)(nil)
+	}
+
+	return d.opts.docErr
+}
+
+type docReqHandler struct {
+	*docHandler
+	docReq
+}
+
+type docResHandler struct {
+	*docHandler
+	docRes
+}
+
+type docReqResHandler struct {
+	*docHandler
+	docReq
+	docRes
+}
@@ -0,0 +1,143 @@
+package chai
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// StatusCoder is implemented by errors that know the HTTP status code they should be reported with.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// ErrorMapping maps the errors matched by an ErrorRegistry entry to a status code.
+// If Message is set it replaces the text of the error in the response, so that internal details are not leaked.
+type ErrorMapping struct {
+	Code    int
+	Message string
+
+	// Target is the sentinel error matched with errors.Is, or nil for mappings registered with RegisterErrorType.
+	Target error
+	// Type is the type of the matched errors: the type of Target, or the type matched with errors.As.
+	Type reflect.Type
+
+	match func(error) bool
+}
+
+// ErrorRegistry maps the errors returned by handlers with a status code of 0 to status codes.
+// Errors that match no mapping but implement StatusCoder use their own status code, all others become 500.
+type ErrorRegistry struct {
+	mappings []ErrorMapping
+}
+
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{}
+}
+
+// Register maps the errors that match target with errors.Is to code.
+func (er *ErrorRegistry) Register(target error, code int, message string) *ErrorRegistry {
+	er.mappings = append(er.mappings, ErrorMapping{
+		Code:    code,
+		Message: message,
+		Target:  target,
+		Type:    reflect.TypeOf(target),
+		match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+	})
+
+	return er
+}
+
+// RegisterErrorType maps the errors that match E with errors.As to code.
+func RegisterErrorType[E error](er *ErrorRegistry, code int, message string) *ErrorRegistry {
+	er.mappings = append(er.mappings, ErrorMapping{
+		Code:    code,
+		Message: message,
+		Type:    reflect.TypeOf((*E)(nil)).Elem(),
+		match: func(err error) bool {
+			return errors.As(err, new(E))
+		},
+	})
+
+	return er
+}
+
+// Mappings returns the mappings of the registry in the order they were registered.
+func (er *ErrorRegistry) Mappings() []ErrorMapping {
+	if er == nil {
+		return nil
+	}
+
+	return er.mappings
+}
+
+// Lookup returns the mapping of err. The first registered mapping that matches wins.
+func (er *ErrorRegistry) Lookup(err error) (ErrorMapping, bool) {
+	for _, m := range er.Mappings() {
+		if m.match(err) {
+			return m, true
+		}
+	}
+
+	var sc StatusCoder
+	if errors.As(err, &sc) && sc.StatusCode() != 0 {
+		return ErrorMapping{Code: sc.StatusCode(), Type: reflect.TypeOf(sc)}, true
+	}
+
+	return ErrorMapping{}, false
+}
+
+// resolve returns the status code and the error to write for an error returned by a handler with the given code.
+func (er *ErrorRegistry) resolve(code int, err error) (int, error) {
+	if code != 0 {
+		return code, err
+	}
+
+	m, ok := er.Lookup(err)
+	if !ok {
+		return http.StatusInternalServerError, err
+	}
+
+	if m.Message != "" {
+		err = &publicError{err: err, message: m.Message}
+	}
+
+	return m.Code, err
+}
+
+// CanProduce reports whether an error of type errType can match m.
+func (m ErrorMapping) CanProduce(errType reflect.Type) bool {
+	if m.Type == nil || errType == nil {
+		return false
+	}
+
+	if errType.Kind() == reflect.Interface {
+		return m.Type.Implements(errType) || m.Type.Kind() == reflect.Interface
+	}
+
+	if m.Type.Kind() == reflect.Interface {
+		return errType.Implements(m.Type)
+	}
+
+	return errType == m.Type
+}
+
+// publicError replaces the text of an error with a public message and hides its JSON members.
+type publicError struct {
+	err     error
+	message string
+}
+
+func (e *publicError) Error() string {
+	return e.message
+}
+
+func (e *publicError) Unwrap() error {
+	return e.err
+}
+
+func (e *publicError) MarshalJSON() ([]byte, error) {
+	return []byte(`{}`), nil
+}
@@ -0,0 +1,141 @@
+package chai
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+const OctetStreamContentType = "application/octet-stream"
+
+// File is a response body that is served as is, with support for Range and conditional requests.
+type File struct {
+	// Name is sent as the filename of the Content-Disposition header, and is used to detect the content type when ContentType is empty.
+	Name    string
+	ModTime time.Time
+	// ContentType is the media type of Content. If it is empty, it is detected from the extension of Name or from the content.
+	ContentType string
+	// ETag is the quoted entity tag of the content, which If-None-Match and If-Range headers are compared with.
+	ETag string
+	// Inline makes browsers display the file instead of downloading it.
+	Inline  bool
+	Content io.ReadSeeker
+}
+
+var (
+	fileType       = reflect.TypeOf(File{})
+	bytesType      = reflect.TypeOf([]byte(nil))
+	readSeekerType = reflect.TypeOf((*io.ReadSeeker)(nil)).Elem()
+	// binaryContentTypes are the documented media types of binary responses when none are set with WithBinaryContentTypes.
+	binaryContentTypes = []string{OctetStreamContentType}
+)
+
+// IsBinary reports whether responses of type t are written as is instead of being encoded: File, *File, []byte and io.ReadSeeker implementations.
+func IsBinary(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	return indirectType(t) == fileType || t == bytesType || t.Implements(readSeekerType)
+}
+
+// BinaryContentTyper is implemented by handlers so that the media types of their binary responses can be documented.
+type BinaryContentTyper interface {
+	BinaryContentTypes() []string
+}
+
+// WithBinaryContentTypes documents the media types of the binary responses of a handler, which default to application/octet-stream.
+func WithBinaryContentTypes(contentTypes ...string) Option {
+	return func(o *options) {
+		o.binaryContentTypes = append(o.binaryContentTypes, contentTypes...)
+	}
+}
+
+// forAccept returns the codec that encodes the responses for the Accept header of r.
+// Binary responses are not encoded, so their Accept header is not checked.
+func (o *options) forAccept(r *http.Request, res any) (Codec, error) {
+	if IsBinary(BodyType(reflect.TypeOf(res).Elem())) {
+		return o.codecs.ForAccept("")
+	}
+
+	return o.codecs.ForAccept(r.Header.Get("Accept"))
+}
+
+// writeBinary writes body as is if it is a binary response, and reports whether it did.
+// Range and conditional requests are only honored for 200 responses. A nil *File or reader is written as no content,
+// with a 204 instead of a 200.
+func writeBinary(w http.ResponseWriter, r *http.Request, code int, body any) bool {
+	var f File
+
+	switch b := body.(type) {
+	case File:
+		f = b
+	case *File:
+		if b == nil {
+			writeNoContent(w, code)
+			return true
+		}
+		f = *b
+	case []byte:
+		f = File{Content: bytes.NewReader(b)}
+	case io.ReadSeeker:
+		if v := reflect.ValueOf(b); v.Kind() == reflect.Pointer && v.IsNil() {
+			writeNoContent(w, code)
+			return true
+		}
+		f = File{Content: b}
+	default:
+		return false
+	}
+
+	if f.Content == nil {
+		f.Content = bytes.NewReader(nil)
+	}
+
+	if c, ok := f.Content.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	switch {
+	case f.ContentType != "":
+		w.Header().Set("Content-Type", f.ContentType)
+	case f.Name == "":
+		w.Header().Set("Content-Type", OctetStreamContentType)
+	}
+
+	if f.ETag != "" {
+		w.Header().Set("ETag", f.ETag)
+	}
+
+	if f.Name != "" {
+		disposition := "attachment"
+		if f.Inline {
+			disposition = "inline"
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": f.Name}))
+	}
+
+	if code != http.StatusOK {
+		w.WriteHeader(code)
+		if r.Method != http.MethodHead {
+			io.Copy(w, f.Content)
+		}
+
+		return true
+	}
+
+	http.ServeContent(w, r, f.Name, f.ModTime, f.Content)
+
+	return true
+}
+
+func writeNoContent(w http.ResponseWriter, code int) {
+	if code == http.StatusOK {
+		code = http.StatusNoContent
+	}
+
+	w.WriteHeader(code)
+}
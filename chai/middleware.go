@@ -0,0 +1,67 @@
+package chai
+
+import (
+	"net/http"
+)
+
+// MiddlewareDocs documents what a middleware contributes to the operations of the routes it is applied to.
+type MiddlewareDocs struct {
+	// Headers are the request headers the middleware reads, e.g. Authorization.
+	Headers []MiddlewareHeader
+	// Security are the security requirements of the operations, e.g. {"ApiKeyAuth": {}}. The schemes must be defined in the document.
+	Security []map[string][]string
+	// Responses maps the status codes of the responses the middleware writes itself, e.g. 401, 403 or 429, to their descriptions.
+	Responses map[int]string
+	Tags      []string
+}
+
+// MiddlewareHeader documents a request header read by a middleware.
+type MiddlewareHeader struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// MiddlewareDocumenter is implemented by the handlers that middlewares return, so that the middlewares can be documented.
+type MiddlewareDocumenter interface {
+	MiddlewareDocs() MiddlewareDocs
+}
+
+// DocumentMiddleware returns a middleware that behaves like mw, and whose contributions to the operations of the routes it is applied to
+// are documented by docs.
+//
+// The docs are read back with MiddlewareDocsOf, which calls every middleware of the documented routes, documented or not,
+// on a handler that does nothing while the docs are generated. Whatever the middlewares do when they wrap a handler runs again then,
+// and only their panics are recovered.
+func DocumentMiddleware(mw func(http.Handler) http.Handler, docs MiddlewareDocs) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return &documentedHandler{Handler: mw(next), docs: docs}
+	}
+}
+
+type documentedHandler struct {
+	http.Handler
+	docs MiddlewareDocs
+}
+
+func (h *documentedHandler) MiddlewareDocs() MiddlewareDocs {
+	return h.docs
+}
+
+// MiddlewareDocsOf returns the docs of mw, and false if it is not documented. The middleware is applied to a handler that does nothing,
+// and is documented if the handler it returns implements MiddlewareDocumenter.
+func MiddlewareDocsOf(mw func(http.Handler) http.Handler) (docs MiddlewareDocs, ok bool) {
+	defer func() {
+		// Middlewares that cannot be applied outside of a router are not documented.
+		if recover() != nil {
+			docs, ok = MiddlewareDocs{}, false
+		}
+	}()
+
+	md, ok := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).(MiddlewareDocumenter)
+	if !ok {
+		return MiddlewareDocs{}, false
+	}
+
+	return md.MiddlewareDocs(), true
+}
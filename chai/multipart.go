@@ -0,0 +1,302 @@
+package chai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+const MultipartContentType = "multipart/form-data"
+
+// ErrFileTooLarge is returned when a file of a multipart/form-data request is larger than its limit.
+var ErrFileTooLarge = errors.New("file is too large")
+
+// multipartMaxMemory is the number of bytes of the files of a request that are kept in memory. Larger files are stored in temporary files.
+const multipartMaxMemory = 32 << 20
+
+const (
+	MultipartValue = "value"
+	MultipartFile  = "file"
+	MultipartFiles = "files"
+	MultipartParts = "parts"
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	partsType       = reflect.TypeOf((*Parts)(nil))
+)
+
+// MultipartField is a request struct field that is filled from a multipart/form-data body.
+type MultipartField struct {
+	// Kind is one of MultipartValue, MultipartFile, MultipartFiles or MultipartParts.
+	Kind  string
+	Name  string
+	Field reflect.StructField
+	Index []int
+	// MaxSize is the limit of the size of the field's files set with the `maxSize` tag, or 0.
+	MaxSize int64
+}
+
+// MultipartFields returns the fields of t (or of the struct t points to) that are filled from a multipart/form-data body.
+//
+// Fields are named by their `form` tag, or their `json` tag when there is none. Fields of type *multipart.FileHeader
+// and []*multipart.FileHeader receive the files of the part with their name, and the size of each file can be limited
+// with a `maxSize:"<bytes>"` tag. All other fields receive the values of the form fields with their name.
+//
+// A field of type *Parts makes the body stream: the form fields are decoded up to the first file,
+// and the files are then read one at a time by the handler with Parts.Next.
+func MultipartFields(t reflect.Type) ([]MultipartField, error) {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	res := make([]MultipartField, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct && !hasParamTag(f) {
+			fields, err := MultipartFields(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			for _, mf := range fields {
+				mf.Index = append([]int{i}, mf.Index...)
+				res = append(res, mf)
+			}
+			continue
+		}
+
+		name := formFieldName(f)
+		if name == "" || hasParamTag(f) {
+			continue
+		}
+
+		mf := MultipartField{Kind: MultipartValue, Name: name, Field: f, Index: []int{i}}
+
+		switch f.Type {
+		case fileHeaderType:
+			mf.Kind = MultipartFile
+		case fileHeadersType:
+			mf.Kind = MultipartFiles
+		case partsType:
+			mf.Kind = MultipartParts
+		}
+
+		if s := f.Tag.Get("maxSize"); s != "" {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: invalid maxSize tag: %w", f.Name, err)
+			}
+			mf.MaxSize = n
+		}
+
+		res = append(res, mf)
+	}
+
+	return res, nil
+}
+
+// Parts gives streaming access to the files of a multipart/form-data request.
+type Parts struct {
+	r        *multipart.Reader
+	next     *multipart.Part
+	maxBytes int64
+}
+
+// Next returns the next part of the body, or io.EOF when there are no more.
+// Reading more than the `maxSize` of the *Parts field, or else the file size limit of the handler, from a part returns ErrFileTooLarge.
+func (p *Parts) Next() (*FilePart, error) {
+	part := p.next
+	p.next = nil
+
+	if part == nil {
+		var err error
+		part, err = p.r.NextPart()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &FilePart{Part: part, r: limitReader(part, p.maxBytes)}, nil
+}
+
+// FilePart is a part of a multipart/form-data body whose reads are limited like those of Parts.Next.
+type FilePart struct {
+	*multipart.Part
+	r io.Reader
+}
+
+func (p *FilePart) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+// limitReader returns a reader that fails with ErrFileTooLarge once more than n bytes are read from r. n <= 0 means no limit.
+func limitReader(r io.Reader, n int64) io.Reader {
+	if n <= 0 {
+		return r
+	}
+
+	return &limitedReader{r: r, n: n}
+}
+
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	if int64(len(b)) > l.n+1 {
+		b = b[:l.n+1]
+	}
+
+	n, err := l.r.Read(b)
+	if int64(n) > l.n {
+		return int(l.n), ErrFileTooLarge
+	}
+	l.n -= int64(n)
+
+	return n, err
+}
+
+// decodeMultipart fills v from the multipart/form-data body of r, and returns the form it read, whose temporary files are removed
+// with removeForm, or nil if the body is left to a *Parts field.
+func decodeMultipart(o *options, r *http.Request, body io.Reader, v reflect.Value) (*multipart.Form, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != MultipartContentType || params["boundary"] == "" {
+		return nil, fmt.Errorf("%w: expected %s", ErrUnsupportedMediaType, MultipartContentType)
+	}
+
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	fields, err := MultipartFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	mr := multipart.NewReader(body, params["boundary"])
+
+	for _, mf := range fields {
+		if mf.Kind == MultipartParts {
+			return nil, decodeMultipartStream(o, mr, v, fields, mf)
+		}
+	}
+
+	form, err := mr.ReadForm(multipartMaxMemory)
+	if err != nil {
+		return nil, err
+	}
+	// The handler function can also read the form from r. The router may have passed a copy of the request to the handler,
+	// so net/http would not see the form to remove its temporary files, which is left to the caller.
+	r.MultipartForm = form
+
+	for _, mf := range fields {
+		fv, err := fieldByIndex(v, mf.Index)
+		if err != nil {
+			return form, err
+		}
+
+		switch mf.Kind {
+		case MultipartValue:
+			if values := form.Value[mf.Name]; len(values) > 0 {
+				if err := setValues(fv, values); err != nil {
+					return form, fmt.Errorf("multipart: field %q: %w", mf.Name, err)
+				}
+			}
+		case MultipartFile, MultipartFiles:
+			files := form.File[mf.Name]
+			if len(files) == 0 {
+				continue
+			}
+
+			maxSize := mf.MaxSize
+			if maxSize == 0 {
+				maxSize = o.maxFileBytes
+			}
+			for _, fh := range files {
+				if maxSize > 0 && fh.Size > maxSize {
+					return form, fmt.Errorf("%w: %s is larger than %d bytes", ErrFileTooLarge, fh.Filename, maxSize)
+				}
+			}
+
+			if mf.Kind == MultipartFile {
+				fv.Set(reflect.ValueOf(files[0]))
+			} else {
+				fv.Set(reflect.ValueOf(files))
+			}
+		}
+	}
+
+	return form, nil
+}
+
+// removeForm removes the temporary files of a form returned by decodeMultipart.
+func removeForm(form *multipart.Form) {
+	if form != nil {
+		form.RemoveAll()
+	}
+}
+
+// decodeMultipartStream decodes the form fields up to the first file and leaves the rest of the body to the *Parts field.
+func decodeMultipartStream(o *options, mr *multipart.Reader, v reflect.Value, fields []MultipartField, partsField MultipartField) error {
+	values := map[string][]string{}
+
+	maxSize := partsField.MaxSize
+	if maxSize == 0 {
+		maxSize = o.maxFileBytes
+	}
+	parts := &Parts{r: mr, maxBytes: maxSize}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if part.FileName() != "" {
+			parts.next = part
+			break
+		}
+
+		b, err := io.ReadAll(limitReader(part, multipartMaxMemory))
+		if err != nil {
+			return err
+		}
+		values[part.FormName()] = append(values[part.FormName()], string(b))
+	}
+
+	for _, mf := range fields {
+		if mf.Kind != MultipartValue || len(values[mf.Name]) == 0 {
+			continue
+		}
+
+		fv, err := fieldByIndex(v, mf.Index)
+		if err != nil {
+			return err
+		}
+
+		if err := setValues(fv, values[mf.Name]); err != nil {
+			return fmt.Errorf("multipart: field %q: %w", mf.Name, err)
+		}
+	}
+
+	fv, err := fieldByIndex(v, partsField.Index)
+	if err != nil {
+		return err
+	}
+	fv.Set(reflect.ValueOf(parts))
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package chai
+
+// OneOfer is implemented by the types whose values are exactly one of several types, e.g. a struct that holds one of several variants
+// and marshals as it. The OpenAPI 3 documents describe them with a oneOf of the schemas of the types of the values OneOf returns,
+// e.g. []any{Cat{}, Dog{}}. OneOf is called on the zero value of the type.
+type OneOfer interface {
+	OneOf() []any
+}
+
+// AnyOfer is implemented by the types whose values match one or more of several types. The OpenAPI 3 documents describe them
+// with an anyOf of the schemas of the types of the values AnyOf returns. AnyOf is called on the zero value of the type.
+type AnyOfer interface {
+	AnyOf() []any
+}
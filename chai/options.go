@@ -0,0 +1,214 @@
+package chai
+
+import (
+	"net/http"
+	"time"
+)
+
+// PathParamFunc returns the value of the named path parameter of the request.
+// Each router adapter provides its own, e.g. chi.URLParam.
+type PathParamFunc func(r *http.Request, name string) string
+
+// ErrorHook is called with every error a handler is about to write, before it is passed to the ErrorWriter.
+type ErrorHook func(r *http.Request, code int, err error)
+
+type Option func(*options)
+
+type options struct {
+	pathParam    PathParamFunc
+	noBody       bool
+	strict       bool
+	multipart    bool
+	maxFileBytes int64
+	codecs       *CodecRegistry
+	errorWriter  ErrorWriter
+	maxBodyBytes int64
+	errorHooks   []ErrorHook
+	errors       *ErrorRegistry
+	debug        bool
+	panicHooks   []PanicHook
+
+	responseHeaders    []ResponseHeader
+	binaryContentTypes []string
+	callbacks          []Callback
+	links              []Link
+
+	pingInterval time.Duration
+	checkOrigin  func(r *http.Request) bool
+
+	docReq       any
+	docRes       any
+	docErr       any
+	undocumented bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		codecs: DefaultCodecs,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.errorWriter == nil {
+		o.errorWriter = DefaultErrorWriter
+	}
+
+	return o
+}
+
+func (o *options) writeErr(w http.ResponseWriter, r *http.Request, code int, e ErrType) {
+	o.runErrorHooks(r, code, e)
+
+	o.errorWriter.WriteError(w, code, e)
+}
+
+func (o *options) runErrorHooks(r *http.Request, code int, e ErrType) {
+	for _, hook := range o.errorHooks {
+		hook(r, code, e)
+	}
+}
+
+// WithPathParamFunc sets the function used to look up values for fields tagged with `path:"..."`.
+func WithPathParamFunc(fn PathParamFunc) Option {
+	return func(o *options) {
+		o.pathParam = fn
+	}
+}
+
+// WithoutBody makes the handler skip decoding the request body. The request type is filled only from the path, query, headers and cookies.
+func WithoutBody() Option {
+	return func(o *options) {
+		o.noBody = true
+	}
+}
+
+// WithStrictDecoding makes the handler reject request bodies with fields the request type does not have,
+// for codecs that implement StrictCodec. The request schemas are then documented with `additionalProperties: false`.
+func WithStrictDecoding() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithMultipart makes the handler decode multipart/form-data request bodies into the request type, see MultipartFields.
+func WithMultipart() Option {
+	return func(o *options) {
+		o.multipart = true
+	}
+}
+
+// WithMaxFileBytes limits the size of each file of a multipart/form-data request, unless the field of the file has its own `maxSize` tag.
+// Larger files are rejected with 413 Request Entity Too Large.
+func WithMaxFileBytes(n int64) Option {
+	return func(o *options) {
+		o.maxFileBytes = n
+	}
+}
+
+// WithCodecs sets the codecs used to decode request bodies and encode responses. The first one is the default.
+func WithCodecs(codecs ...Codec) Option {
+	return func(o *options) {
+		o.codecs = NewCodecRegistry(codecs...)
+	}
+}
+
+// WithErrorWriter sets the ErrorWriter used instead of DefaultErrorWriter, e.g. ProblemErrorWriter{}.
+func WithErrorWriter(ew ErrorWriter) Option {
+	return func(o *options) {
+		o.errorWriter = ew
+	}
+}
+
+// WithErrors sets the registry used to find the status code of errors returned with a status code of 0.
+func WithErrors(er *ErrorRegistry) Option {
+	return func(o *options) {
+		o.errors = er
+	}
+}
+
+// WithMaxBodyBytes limits the size of request bodies. Larger bodies are rejected with 413 Request Entity Too Large.
+// A limit of 0 or less disables the check.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBodyBytes = n
+	}
+}
+
+// WithErrorHook adds a hook that is called with every error the handler writes, e.g. for logging.
+// Hooks run in the order they were added, including those inherited from a Config.
+func WithErrorHook(hook ErrorHook) Option {
+	return func(o *options) {
+		o.errorHooks = append(o.errorHooks, hook)
+	}
+}
+
+// WithDebug includes the stack trace of recovered panics in the error response. It should not be enabled in production.
+func WithDebug(debug bool) Option {
+	return func(o *options) {
+		o.debug = debug
+	}
+}
+
+// WithPanicHook adds a hook that is called with every panic recovered from the handler, before the 500 error is written.
+func WithPanicHook(hook PanicHook) Option {
+	return func(o *options) {
+		o.panicHooks = append(o.panicHooks, hook)
+	}
+}
+
+// Config is a set of options shared by every handler of a router.
+// Router adapters apply it before the options passed to each handler, so per-handler options take precedence.
+type Config struct {
+	opts []Option
+}
+
+func NewConfig(opts ...Option) *Config {
+	return &Config{opts: opts}
+}
+
+// With returns a new Config with opts added after the options of c.
+func (c *Config) With(opts ...Option) *Config {
+	res := make([]Option, 0, len(c.opts)+len(opts))
+	res = append(res, c.opts...)
+	res = append(res, opts...)
+
+	return &Config{opts: res}
+}
+
+// Options returns the options of c followed by opts.
+func (c *Config) Options(opts ...Option) []Option {
+	if c == nil {
+		return opts
+	}
+
+	return c.With(opts...).opts
+}
+
+// WithConfig applies the options of c. Options that follow it override them.
+func WithConfig(c *Config) Option {
+	return func(o *options) {
+		if c == nil {
+			return
+		}
+
+		for _, opt := range c.opts {
+			opt(o)
+		}
+	}
+}
+
+// Configurer is implemented by routers that carry a Config for the handlers registered on them.
+type Configurer interface {
+	Config() *Config
+}
+
+// ConfigOf returns the Config of r, or nil if r does not carry one.
+func ConfigOf(r any) *Config {
+	if c, ok := r.(Configurer); ok {
+		return c.Config()
+	}
+
+	return nil
+}
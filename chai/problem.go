@@ -0,0 +1,133 @@
+package chai
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object.
+// Extensions holds any additional members, which are marshalled next to the standard ones.
+type Problem struct {
+	Type       string         `json:"type,omitempty"`
+	Title      string         `json:"title,omitempty"`
+	Status     int            `json:"status,omitempty"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+
+	return p.Title
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	type problem Problem
+	b, err := json.Marshal((*problem)(p))
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(m)
+}
+
+// NewProblem builds the problem details for an error returned with the given status code.
+// The JSON members of the error become extension members, and its "type", "title", "detail" and "instance" members,
+// if it has any, override the defaults. A *Problem is copied. The status is always code, so that it matches the status of the response.
+func NewProblem(code int, e ErrType) *Problem {
+	if p, ok := e.(*Problem); ok {
+		cp := *p
+		cp.Status = code
+
+		return &cp
+	}
+
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: e.Error(),
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return p
+	}
+
+	ext := make(map[string]any)
+	if json.Unmarshal(b, &ext) != nil {
+		return p
+	}
+
+	for k, v := range ext {
+		s, isString := v.(string)
+
+		switch k {
+		case "type":
+			if isString {
+				p.Type = s
+			}
+		case "title":
+			if isString {
+				p.Title = s
+			}
+		case "detail":
+			if isString {
+				p.Detail = s
+			}
+		case "instance":
+			if isString {
+				p.Instance = s
+			}
+		case "status":
+			// the status is always the status code of the response
+		default:
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]any)
+			}
+			p.Extensions[k] = v
+		}
+	}
+
+	return p
+}
+
+// ProblemErrorWriter writes errors as application/problem+json.
+type ProblemErrorWriter struct{}
+
+func (ProblemErrorWriter) WriteError(w http.ResponseWriter, code int, e ErrType) {
+	p := NewProblem(code, e)
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		b, _ = json.Marshal(&Problem{Type: "about:blank", Title: http.StatusText(code), Status: code, Detail: e.Error()})
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(code)
+	w.Write(b)
+}
+
+func (ProblemErrorWriter) ErrorType() any {
+	return (*Problem)(nil)
+}
+
+func (ProblemErrorWriter) ErrorContentType() string {
+	return ProblemContentType
+}
@@ -0,0 +1,64 @@
+package chai
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is the error written when a handler panics.
+// Its stack trace is only included in the response when the handler runs in debug mode.
+type PanicError struct {
+	Value any    `json:"-"`
+	Stack string `json:"stack,omitempty"`
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// PanicHook is called with every panic recovered from a handler, e.g. to report it to an error tracker.
+type PanicHook func(r *http.Request, err *PanicError)
+
+// recoverPanic must be deferred by ServeHTTP. It writes a recovered panic as a 500 error.
+// http.ErrAbortHandler is re-panicked so that net/http aborts the response as usual.
+func (o *options) recoverPanic(w http.ResponseWriter, r *http.Request) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	if v == http.ErrAbortHandler {
+		panic(v)
+	}
+
+	pe := &PanicError{Value: v, Stack: string(debug.Stack())}
+
+	for _, hook := range o.panicHooks {
+		hook(r, pe)
+	}
+
+	var err error = &publicError{err: pe, message: http.StatusText(http.StatusInternalServerError)}
+	if o.debug {
+		err = pe
+	}
+
+	o.writePanicErr(w, r, err)
+}
+
+// writePanicErr writes err, falling back to a plain text response if the error writer panics as well.
+func (o *options) writePanicErr(w http.ResponseWriter, r *http.Request, err error) {
+	defer func() {
+		if recover() != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}()
+
+	o.writeErr(w, r, http.StatusInternalServerError, err)
+}
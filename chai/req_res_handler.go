@@ -1,45 +1,67 @@
 package chai
 
 import (
-	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"reflect"
 )
 
 type ReqResHandlerFunc[Req any, Res any, Err ErrType] func(Req, http.ResponseWriter, *http.Request) (Res, int, Err)
 
-func NewReqResHandler[Req any, Res any, Err ErrType](h ReqResHandlerFunc[Req, Res, Err]) *ReqResHandler[Req, Res, Err] {
+func NewReqResHandler[Req any, Res any, Err ErrType](h ReqResHandlerFunc[Req, Res, Err], opts ...Option) *ReqResHandler[Req, Res, Err] {
 	return &ReqResHandler[Req, Res, Err]{
-		f: h,
+		f:    h,
+		opts: newOptions(opts),
 	}
 }
 
 type ReqResHandler[Req any, Res any, Err ErrType] struct {
-	f   ReqResHandlerFunc[Req, Res, Err]
-	req *Req
-	res *Res
-	err *Err
+	f    ReqResHandlerFunc[Req, Res, Err]
+	opts *options
+	req  *Req
+	res  *Res
+	err  *Err
 }
 
 func isErr[Err ErrType](err Err) bool {
 	return !reflect.ValueOf(&err).Elem().IsZero()
 }
 
+// newReq allocates a Req, including the value it points to when Req is a pointer type.
+func newReq[Req any]() *Req {
+	req := new(Req)
+
+	v := reflect.ValueOf(req).Elem()
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+
+	return req
+}
+
 func (h *ReqResHandler[Req, Res, Err]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var req *Req
+	defer h.opts.recoverPanic(w, r)
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErr(w, http.StatusBadRequest, err)
+	enc, err := h.opts.forAccept(r, h.res)
+	if err != nil {
+		h.opts.writeErr(w, r, http.StatusNotAcceptable, err)
 		return
 	}
 
-	res, code, err := h.f(*req, w, r)
-	if isErr(err) {
-		if code == 0 {
-			code = http.StatusInternalServerError
-		}
+	req, form, code, err := readReq[Req](h.opts, w, r)
+	defer removeForm(form)
+	if err != nil {
+		h.opts.writeErr(w, r, code, err)
+		return
+	}
+
+	res, code, herr := h.f(*req, w, r)
+	if isErr(herr) {
+		code, err := h.opts.errors.resolve(code, herr)
 
-		writeErr(w, code, err)
+		h.opts.writeErr(w, r, code, err)
 		return
 	}
 
@@ -47,13 +69,151 @@ func (h *ReqResHandler[Req, Res, Err]) ServeHTTP(w http.ResponseWriter, r *http.
 		code = http.StatusOK
 	}
 
-	write(w, code, res)
+	writeRes(w, r, enc, code, res)
+}
+
+// readReq decodes, binds and validates the request. The body is decoded only if the request type has body fields, see HasBody,
+// like it is documented. On failure it returns the status code to write the error with.
+//
+// It also returns the multipart form the request was decoded from, if any, even on failure. The caller removes its temporary files with removeForm
+// once the handler function returns.
+func readReq[Req any](o *options, w http.ResponseWriter, r *http.Request) (*Req, *multipart.Form, int, error) {
+	req := newReq[Req]()
+
+	var form *multipart.Form
+
+	if o.multipart {
+		body := r.Body
+		if o.maxBodyBytes > 0 {
+			body = http.MaxBytesReader(w, r.Body, o.maxBodyBytes)
+		}
+
+		var err error
+		form, err = decodeMultipart(o, r, body, reflect.ValueOf(req).Elem())
+		if err != nil {
+			return nil, form, bodyErrorCode(err), err
+		}
+	} else if !o.noBody && HasBody(reflect.TypeOf(req)) {
+		dec, err := o.codecs.ForContentType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, nil, http.StatusUnsupportedMediaType, err
+		}
+
+		body := r.Body
+		if o.maxBodyBytes > 0 {
+			body = http.MaxBytesReader(w, r.Body, o.maxBodyBytes)
+		}
+
+		if err := decode(o, dec, body, req); err != nil {
+			return nil, nil, bodyErrorCode(err), err
+		}
+	}
+
+	absent, err := bind(reflect.ValueOf(req).Elem(), r, o.pathParam)
+	if err != nil {
+		return nil, form, http.StatusBadRequest, err
+	}
+
+	if err := validate(*req, absent); err != nil {
+		if errors.As(err, new(*ValidationError)) {
+			return nil, form, http.StatusUnprocessableEntity, err
+		}
+
+		return nil, form, http.StatusInternalServerError, err
+	}
+
+	return req, form, 0, nil
+}
+
+// bodyErrorCode returns the status code of an error returned while reading the request body.
+func bodyErrorCode(err error) int {
+	switch {
+	case errors.As(err, new(*http.MaxBytesError)), errors.Is(err, ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// decode decodes the body into req. Empty bodies, and null bodies for pointer request types, are reported as ErrMissingBody.
+func decode[Req any](o *options, dec Codec, body io.Reader, req *Req) error {
+	var err error
+	if sc, ok := dec.(StrictCodec); ok && o.strict {
+		err = sc.DecodeStrict(body, req)
+	} else {
+		err = dec.Decode(body, req)
+	}
+
+	if err == io.EOF {
+		return ErrMissingBody
+	}
+	if err != nil {
+		return err
+	}
+
+	if v := reflect.ValueOf(req).Elem(); v.Kind() == reflect.Pointer && v.IsNil() {
+		return ErrMissingBody
+	}
+
+	return nil
 }
 
 func (h *ReqResHandler[Req, Res, Err]) Req() any {
 	return h.req
 }
 
+func (h *ReqResHandler[Req, Res, Err]) DecodesBody() bool {
+	return !h.opts.noBody
+}
+
+func (h *ReqResHandler[Req, Res, Err]) DecodesStrictly() bool {
+	return h.opts.strict
+}
+
+func (h *ReqResHandler[Req, Res, Err]) ContentTypes() []string {
+	return h.opts.codecs.ContentTypes()
+}
+
+// RequestContentTypes returns the media types of the request bodies the handler decodes.
+func (h *ReqResHandler[Req, Res, Err]) RequestContentTypes() []string {
+	if h.opts.multipart {
+		return []string{MultipartContentType}
+	}
+
+	return h.opts.codecs.ContentTypes()
+}
+
+// BinaryContentTypes returns the media types of the handler's binary responses.
+func (h *ReqResHandler[Req, Res, Err]) BinaryContentTypes() []string {
+	if len(h.opts.binaryContentTypes) == 0 {
+		return binaryContentTypes
+	}
+
+	return h.opts.binaryContentTypes
+}
+
+func (h *ReqResHandler[Req, Res, Err]) ErrorWriter() ErrorWriter {
+	return h.opts.errorWriter
+}
+
+func (h *ReqResHandler[Req, Res, Err]) ErrorRegistry() *ErrorRegistry {
+	return h.opts.errors
+}
+
+func (h *ReqResHandler[Req, Res, Err]) Callbacks() []Callback {
+	return h.opts.callbacks
+}
+
+func (h *ReqResHandler[Req, Res, Err]) Links() []Link {
+	return h.opts.links
+}
+
+func (h *ReqResHandler[Req, Res, Err]) ResponseHeaders() []ResponseHeader {
+	return h.opts.responseHeaders
+}
+
 func (h *ReqResHandler[Req, Res, Err]) Res() any {
 	return h.res
 }
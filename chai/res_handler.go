@@ -6,26 +6,34 @@ import (
 
 type ResHandlerFunc[Res any, Err ErrType] func(http.ResponseWriter, *http.Request) (Res, int, Err)
 
-func NewResHandler[Res any, Err ErrType](h ResHandlerFunc[Res, Err]) *ResHandler[Res, Err] {
+func NewResHandler[Res any, Err ErrType](h ResHandlerFunc[Res, Err], opts ...Option) *ResHandler[Res, Err] {
 	return &ResHandler[Res, Err]{
-		f: h,
+		f:    h,
+		opts: newOptions(opts),
 	}
 }
 
 type ResHandler[Res any, Err ErrType] struct {
-	f   ResHandlerFunc[Res, Err]
-	res *Res
-	err *Err
+	f    ResHandlerFunc[Res, Err]
+	opts *options
+	res  *Res
+	err  *Err
 }
 
 func (h *ResHandler[Res, Err]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	res, code, err := h.f(w, r)
-	if isErr(err) {
-		if code == 0 {
-			code = http.StatusInternalServerError
-		}
+	defer h.opts.recoverPanic(w, r)
 
-		writeErr(w, code, err)
+	enc, err := h.opts.forAccept(r, h.res)
+	if err != nil {
+		h.opts.writeErr(w, r, http.StatusNotAcceptable, err)
+		return
+	}
+
+	res, code, herr := h.f(w, r)
+	if isErr(herr) {
+		code, err := h.opts.errors.resolve(code, herr)
+
+		h.opts.writeErr(w, r, code, err)
 		return
 	}
 
@@ -33,7 +41,40 @@ func (h *ResHandler[Res, Err]) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		code = http.StatusOK
 	}
 
-	write(w, code, res)
+	writeRes(w, r, enc, code, res)
+}
+
+func (h *ResHandler[Res, Err]) ContentTypes() []string {
+	return h.opts.codecs.ContentTypes()
+}
+
+// BinaryContentTypes returns the media types of the handler's binary responses.
+func (h *ResHandler[Res, Err]) BinaryContentTypes() []string {
+	if len(h.opts.binaryContentTypes) == 0 {
+		return binaryContentTypes
+	}
+
+	return h.opts.binaryContentTypes
+}
+
+func (h *ResHandler[Res, Err]) ErrorWriter() ErrorWriter {
+	return h.opts.errorWriter
+}
+
+func (h *ResHandler[Res, Err]) ErrorRegistry() *ErrorRegistry {
+	return h.opts.errors
+}
+
+func (h *ResHandler[Res, Err]) Callbacks() []Callback {
+	return h.opts.callbacks
+}
+
+func (h *ResHandler[Res, Err]) Links() []Link {
+	return h.opts.links
+}
+
+func (h *ResHandler[Res, Err]) ResponseHeaders() []ResponseHeader {
+	return h.opts.responseHeaders
 }
 
 func (h *ResHandler[Res, Err]) Res() any {
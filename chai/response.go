@@ -0,0 +1,126 @@
+package chai
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Response can be returned by handlers in place of their response body to also set the status code, headers and cookies.
+// A non-zero Status takes precedence over the status code returned by the handler.
+type Response[T any] struct {
+	Status  int
+	Header  http.Header
+	Cookies []*http.Cookie
+	Body    T
+}
+
+// Envelope returns the parts of the response that are written besides the body, and the body itself.
+func (r Response[T]) Envelope() (int, http.Header, []*http.Cookie, any) {
+	return r.Status, r.Header, r.Cookies, r.Body
+}
+
+// BodyType returns a nil *T, which is used to document the response body.
+func (r Response[T]) BodyType() any {
+	return (*T)(nil)
+}
+
+// Enveloper is implemented by Response.
+type Enveloper interface {
+	Envelope() (status int, header http.Header, cookies []*http.Cookie, body any)
+	BodyType() any
+}
+
+// ResponseHeader documents a header that a handler sets on its responses.
+type ResponseHeader struct {
+	// Code is the status code of the responses the header is set on, or 0 for all successful responses.
+	Code        int
+	Name        string
+	Type        string
+	Description string
+}
+
+// ResponseHeaderer is implemented by handlers so that the headers declared with WithResponseHeader can be documented.
+type ResponseHeaderer interface {
+	ResponseHeaders() []ResponseHeader
+}
+
+// WithResponseHeader documents a string header set on the responses with the given status code, or on all successful responses if code is 0.
+func WithResponseHeader(code int, name, description string) Option {
+	return func(o *options) {
+		o.responseHeaders = append(o.responseHeaders, ResponseHeader{Code: code, Name: name, Type: "string", Description: description})
+	}
+}
+
+// Link documents how values of the responses can be used as the parameters of another operation, in OpenAPI 3 documents.
+type Link struct {
+	// Code is the status code of the responses the link is added to, or 0 for all successful responses.
+	Code        int
+	Name        string
+	OperationID string
+	// Parameters maps the names of the parameters of the linked operation to runtime expressions, e.g. "$response.body#/id".
+	Parameters map[string]string
+}
+
+// Linker is implemented by handlers so that the links declared with WithLink can be documented.
+type Linker interface {
+	Links() []Link
+}
+
+// WithLink documents a link named name from the responses with the given status code, or from all successful responses if code is 0,
+// to the operation with the ID operationID.
+func WithLink(code int, name, operationID string, params map[string]string) Option {
+	return func(o *options) {
+		o.links = append(o.links, Link{Code: code, Name: name, OperationID: operationID, Parameters: params})
+	}
+}
+
+// BodyType returns the type of the body of responses of type t: the type of T for a Response[T] or *Response[T], and t itself otherwise.
+func BodyType(t reflect.Type) reflect.Type {
+	et := indirectType(t)
+
+	if env, ok := reflect.Zero(et).Interface().(Enveloper); ok {
+		return reflect.TypeOf(env.BodyType()).Elem()
+	}
+
+	return t
+}
+
+// writeRes writes the response returned by a handler, applying the status code, headers and cookies of a Response.
+// Binary bodies are written as is, other bodies are encoded with codec. Nil binary responses have no content.
+func writeRes(w http.ResponseWriter, r *http.Request, codec Codec, code int, res any) {
+	if v := reflect.ValueOf(res); v.Kind() == reflect.Pointer && v.IsNil() {
+		if IsBinary(BodyType(v.Type())) {
+			writeNoContent(w, code)
+			return
+		}
+
+		write(w, codec, code, res)
+		return
+	}
+
+	if env, ok := res.(Enveloper); ok {
+		status, header, cookies, body := env.Envelope()
+
+		for name, values := range header {
+			for _, v := range values {
+				w.Header().Add(name, v)
+			}
+		}
+
+		for _, c := range cookies {
+			http.SetCookie(w, c)
+		}
+
+		if status != 0 {
+			code = status
+		}
+
+		res = body
+	}
+
+	if writeBinary(w, r, code, res) {
+		return
+	}
+
+	write(w, codec, code, res)
+}
@@ -0,0 +1,194 @@
+package chai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const EventStreamContentType = "text/event-stream"
+
+var ErrStreamingUnsupported = errors.New("streaming is not supported by the response writer")
+
+// Event is a Server-Sent Event. Data is written as JSON, except for strings which are written as is.
+type Event[T any] struct {
+	// Name is the event type, written as the `event` field. Clients receive unnamed events as "message".
+	Name string
+	// ID is written as the `id` field. Clients send the ID of the last event they received in the Last-Event-ID header when they reconnect.
+	ID string
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+	Data  T
+}
+
+// SSEHandlerFunc returns the channel of events to send to the client. The stream ends when the channel is closed or the client disconnects,
+// so the function should stop sending once r.Context() is done. Errors returned with a nil channel are written by the ErrorWriter.
+//
+// Clients that reconnect send the Last-Event-ID header, which can be bound to a field of the request type with `header:"Last-Event-ID"`.
+type SSEHandlerFunc[Req any, T any, Err ErrType] func(Req, http.ResponseWriter, *http.Request) (<-chan Event[T], int, Err)
+
+func NewSSEHandler[Req any, T any, Err ErrType](h SSEHandlerFunc[Req, T, Err], opts ...Option) *SSEHandler[Req, T, Err] {
+	return &SSEHandler[Req, T, Err]{
+		f:    h,
+		opts: newOptions(append([]Option{WithoutBody()}, opts...)),
+	}
+}
+
+// SSEHandler streams the events returned by its handler function as text/event-stream.
+// The request type is filled from the path, query, headers and cookies like with WithoutBody.
+type SSEHandler[Req any, T any, Err ErrType] struct {
+	f    SSEHandlerFunc[Req, T, Err]
+	opts *options
+	req  *Req
+	res  *T
+	err  *Err
+}
+
+func (h *SSEHandler[Req, T, Err]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer h.opts.recoverPanic(w, r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.opts.writeErr(w, r, http.StatusInternalServerError, ErrStreamingUnsupported)
+		return
+	}
+
+	req, form, code, err := readReq[Req](h.opts, w, r)
+	defer removeForm(form)
+	if err != nil {
+		h.opts.writeErr(w, r, code, err)
+		return
+	}
+
+	events, code, herr := h.f(*req, w, r)
+	if isErr(herr) {
+		code, err := h.opts.errors.resolve(code, herr)
+
+		h.opts.writeErr(w, r, code, err)
+		return
+	}
+
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(code)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+
+			b, err := marshalEvent(ev)
+			if err != nil {
+				// The status code is already written, so the error is only passed to the error hooks, and the stream ends.
+				h.opts.runErrorHooks(r, http.StatusInternalServerError, err)
+				return
+			}
+
+			if _, err := w.Write(b); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// marshalEvent returns the fields of an event, followed by the blank line that dispatches it.
+func marshalEvent[T any](ev Event[T]) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if ev.ID != "" {
+		fmt.Fprintf(&buf, "id: %s\n", eventField(ev.ID))
+	}
+
+	if ev.Name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", eventField(ev.Name))
+	}
+
+	if ev.Retry > 0 {
+		fmt.Fprintf(&buf, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+
+	var data string
+	if s, ok := any(ev.Data).(string); ok {
+		data = s
+	} else {
+		b, err := json.Marshal(ev.Data)
+		if err != nil {
+			return nil, err
+		}
+		data = string(b)
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// eventField removes the line breaks that would end a field early.
+func eventField(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// LastEventID returns the ID of the last event received by a reconnecting client.
+func LastEventID(r *http.Request) string {
+	return r.Header.Get("Last-Event-ID")
+}
+
+func (h *SSEHandler[Req, T, Err]) Req() any {
+	return h.req
+}
+
+func (h *SSEHandler[Req, T, Err]) DecodesBody() bool {
+	return false
+}
+
+func (h *SSEHandler[Req, T, Err]) ContentTypes() []string {
+	return []string{EventStreamContentType}
+}
+
+func (h *SSEHandler[Req, T, Err]) StreamContentTypes() []string {
+	return []string{EventStreamContentType}
+}
+
+func (h *SSEHandler[Req, T, Err]) ErrorWriter() ErrorWriter {
+	return h.opts.errorWriter
+}
+
+func (h *SSEHandler[Req, T, Err]) ErrorRegistry() *ErrorRegistry {
+	return h.opts.errors
+}
+
+func (h *SSEHandler[Req, T, Err]) ResponseHeaders() []ResponseHeader {
+	return h.opts.responseHeaders
+}
+
+// Res returns a nil *T, the type of the event payloads.
+func (h *SSEHandler[Req, T, Err]) Res() any {
+	return h.res
+}
+
+func (h *SSEHandler[Req, T, Err]) Err() any {
+	return h.err
+}
+
+func (h *SSEHandler[Req, T, Err]) Handler() any {
+	return h.f
+}
@@ -0,0 +1,177 @@
+package chai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const NDJSONContentType = "application/x-ndjson"
+
+// streamContentTypes are the media types a StreamHandler can write: one JSON value per line, or a single JSON array.
+var streamContentTypes = []string{NDJSONContentType, "application/json"}
+
+// StreamHandlerFunc returns the channel of items to send to the client. The stream ends when the channel is closed or the client disconnects,
+// so the function should stop sending once r.Context() is done. The context is also canceled when an item cannot be marshalled,
+// which aborts the response, see http.ErrAbortHandler, so that clients do not mistake the items written so far for the whole stream.
+// Errors returned with a nil channel are written by the ErrorWriter.
+type StreamHandlerFunc[Req any, T any, Err ErrType] func(Req, http.ResponseWriter, *http.Request) (<-chan T, int, Err)
+
+func NewStreamHandler[Req any, T any, Err ErrType](h StreamHandlerFunc[Req, T, Err], opts ...Option) *StreamHandler[Req, T, Err] {
+	return &StreamHandler[Req, T, Err]{
+		f:    h,
+		opts: newOptions(opts),
+	}
+}
+
+// StreamHandler writes the items returned by its handler function one at a time, as application/x-ndjson
+// or, if the client only accepts application/json, as the elements of a JSON array.
+// Items are read from the channel only as fast as they can be written, and the response is flushed whenever no item is ready.
+type StreamHandler[Req any, T any, Err ErrType] struct {
+	f    StreamHandlerFunc[Req, T, Err]
+	opts *options
+	req  *Req
+	res  *T
+	err  *Err
+}
+
+func (h *StreamHandler[Req, T, Err]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer h.opts.recoverPanic(w, r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.opts.writeErr(w, r, http.StatusInternalServerError, ErrStreamingUnsupported)
+		return
+	}
+
+	i, err := negotiate(r.Header.Get("Accept"), streamContentTypes)
+	if err != nil {
+		h.opts.writeErr(w, r, http.StatusNotAcceptable, err)
+		return
+	}
+	contentType := streamContentTypes[i]
+	array := contentType != NDJSONContentType
+
+	req, form, code, err := readReq[Req](h.opts, w, r)
+	defer removeForm(form)
+	if err != nil {
+		h.opts.writeErr(w, r, code, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	r = r.WithContext(ctx)
+
+	items, code, herr := h.f(*req, w, r)
+	if isErr(herr) {
+		code, err := h.opts.errors.resolve(code, herr)
+
+		h.opts.writeErr(w, r, code, err)
+		return
+	}
+
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+
+	if array {
+		w.Write([]byte("["))
+	}
+
+	for n := 0; ; n++ {
+		var item T
+
+		select {
+		case item, ok = <-items:
+		default:
+			flusher.Flush()
+
+			select {
+			case item, ok = <-items:
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if !ok {
+			break
+		}
+
+		b, err := json.Marshal(item)
+		if err != nil {
+			// The status code is already written, so the error is only passed to the error hooks,
+			// and the response is aborted instead of being ended like a complete stream.
+			cancel()
+			h.opts.runErrorHooks(r, http.StatusInternalServerError, err)
+			flusher.Flush()
+			panic(http.ErrAbortHandler)
+		}
+
+		switch {
+		case !array:
+			b = append(b, '\n')
+		case n > 0:
+			b = append([]byte(","), b...)
+		}
+
+		if _, err := w.Write(b); err != nil {
+			return
+		}
+	}
+
+	if array {
+		w.Write([]byte("]\n"))
+	}
+
+	flusher.Flush()
+}
+
+func (h *StreamHandler[Req, T, Err]) Req() any {
+	return h.req
+}
+
+func (h *StreamHandler[Req, T, Err]) DecodesBody() bool {
+	return !h.opts.noBody
+}
+
+func (h *StreamHandler[Req, T, Err]) DecodesStrictly() bool {
+	return h.opts.strict
+}
+
+// ContentTypes returns the media types of the request bodies the handler decodes.
+func (h *StreamHandler[Req, T, Err]) ContentTypes() []string {
+	return h.opts.codecs.ContentTypes()
+}
+
+func (h *StreamHandler[Req, T, Err]) StreamContentTypes() []string {
+	return streamContentTypes
+}
+
+func (h *StreamHandler[Req, T, Err]) ErrorWriter() ErrorWriter {
+	return h.opts.errorWriter
+}
+
+func (h *StreamHandler[Req, T, Err]) ErrorRegistry() *ErrorRegistry {
+	return h.opts.errors
+}
+
+func (h *StreamHandler[Req, T, Err]) ResponseHeaders() []ResponseHeader {
+	return h.opts.responseHeaders
+}
+
+// Res returns a nil *T, the type of the items of the stream.
+func (h *StreamHandler[Req, T, Err]) Res() any {
+	return h.res
+}
+
+func (h *StreamHandler[Req, T, Err]) Err() any {
+	return h.err
+}
+
+func (h *StreamHandler[Req, T, Err]) Handler() any {
+	return h.f
+}
@@ -0,0 +1,424 @@
+package chai
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError lists every field of a request that failed validation.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + " " + fe.Message
+	}
+
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// FieldError describes a single failed rule. Field is the JSON path of the field, or the parameter name for fields bound from the path, query, headers or cookies.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Rules are the validation rules declared on a struct field.
+//
+// They are read from the same tags that are used to generate the schema:
+//
+//	validate:"required,min=1,max=10,len=5,oneof=a b c"  (also accepted as `binding`)
+//	minimum:"1" maximum:"10"                         numbers
+//	minLength:"1" maxLength:"10"                     strings
+//	enums:"a,b,c"
+//	pattern:"^[a-z]+$"
+//	format:"email"                                   email, uuid, date, date-time, uri, ipv4, ipv6
+//
+// min, max and len apply to the value of numbers and to the length of strings, slices and maps.
+// The rules are checked for every value, including zero values, except for the nil pointers of optional fields
+// and the optional parameters that are missing from the request, which are absent.
+// Use a pointer for an optional body field whose zero value would not pass its rules.
+type Rules struct {
+	Required bool
+	Min      *float64
+	Max      *float64
+	Len      *int
+	Enum     []string
+	Pattern  *regexp.Regexp
+	Format   string
+}
+
+func (r *Rules) IsZero() bool {
+	return !r.Required && r.Min == nil && r.Max == nil && r.Len == nil && r.Enum == nil && r.Pattern == nil && r.Format == ""
+}
+
+var rulesCache sync.Map
+
+// FieldRules parses the validation rules of f.
+func FieldRules(f reflect.StructField) (*Rules, error) {
+	key := fieldKey{f.Type, f.Tag}
+	if r, ok := rulesCache.Load(key); ok {
+		return r.(*Rules), nil
+	}
+
+	r, err := parseRules(f)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesCache.Store(key, r)
+
+	return r, nil
+}
+
+type fieldKey struct {
+	t   reflect.Type
+	tag reflect.StructTag
+}
+
+func parseRules(f reflect.StructField) (*Rules, error) {
+	r := &Rules{}
+	kind := indirectType(f.Type).Kind()
+	isNumber := isNumberKind(kind)
+
+	for _, tag := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(f.Tag.Get(tag), ",") {
+			name, arg, _ := strings.Cut(rule, "=")
+			arg = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(arg)
+
+			switch name {
+			case "required":
+				r.Required = true
+			case "min", "gte":
+				n, err := strconv.ParseFloat(arg, 64)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: invalid %s rule: %w", f.Name, name, err)
+				}
+				r.Min = &n
+			case "max", "lte":
+				n, err := strconv.ParseFloat(arg, 64)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: invalid %s rule: %w", f.Name, name, err)
+				}
+				r.Max = &n
+			case "len":
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: invalid len rule: %w", f.Name, err)
+				}
+				r.Len = &n
+			case "oneof":
+				r.Enum = strings.Fields(arg)
+			}
+		}
+	}
+
+	bounds := []struct {
+		tag   string
+		dst   **float64
+		check bool
+	}{
+		{"minimum", &r.Min, isNumber},
+		{"maximum", &r.Max, isNumber},
+		{"minLength", &r.Min, kind == reflect.String},
+		{"maxLength", &r.Max, kind == reflect.String},
+	}
+	for _, b := range bounds {
+		s := f.Tag.Get(b.tag)
+		if s == "" || !b.check {
+			continue
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: invalid %s tag: %w", f.Name, b.tag, err)
+		}
+		*b.dst = &n
+	}
+
+	if enums := f.Tag.Get("enums"); enums != "" {
+		r.Enum = strings.Split(enums, ",")
+	}
+
+	if pattern := f.Tag.Get("pattern"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: invalid pattern tag: %w", f.Name, err)
+		}
+		r.Pattern = re
+	}
+
+	if format := f.Tag.Get("format"); formats[format] != nil {
+		r.Format = format
+	}
+
+	return r, nil
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var formats = map[string]func(string) bool{
+	"email": func(s string) bool {
+		a, err := mail.ParseAddress(s)
+		return err == nil && a.Address == s
+	},
+	"uuid": uuidRegexp.MatchString,
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	},
+}
+
+// Validate checks v against the rules declared on its fields and returns a *ValidationError listing every failure.
+func Validate(v any) error {
+	return validate(v, nil)
+}
+
+// validate is like Validate, but does not check the rules of the optional parameters that are absent from the request.
+func validate(v any, absent map[paramKey]bool) error {
+	errs := make([]FieldError, 0)
+
+	err := validateValue(reflect.ValueOf(v), "", absent, &errs)
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+func validateValue(v reflect.Value, path string, absent map[paramKey]bool, errs *[]FieldError) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return nil
+		}
+		return validateStruct(v, path, absent, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), absent, errs)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			err := validateValue(iter.Value(), joinPath(path, fmt.Sprint(iter.Key().Interface())), absent, errs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateStruct(v reflect.Value, path string, absent map[paramKey]bool, errs *[]FieldError) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct && !hasParamTag(f) {
+			err := validateValue(fv, path, absent, errs)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		fieldPath := name
+		if !hasParamTag(f) {
+			fieldPath = joinPath(path, name)
+		}
+
+		rules, err := FieldRules(f)
+		if err != nil {
+			return err
+		}
+
+		if absent[paramKeyOf(f)] && !rules.Required {
+			continue
+		}
+
+		for _, fe := range rules.check(fv) {
+			fe.Field = fieldPath
+			*errs = append(*errs, fe)
+		}
+
+		err = validateValue(fv, fieldPath, absent, errs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the name a field is known by in requests: its parameter name, its JSON name, or its form name.
+func fieldName(f reflect.StructField) (string, bool) {
+	for _, in := range paramLocations {
+		if name, ok := f.Tag.Lookup(in); ok {
+			return name, name != "" && name != "-"
+		}
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = formFieldName(f)
+	}
+
+	return name, name != ""
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (r *Rules) check(v reflect.Value) []FieldError {
+	if r.IsZero() {
+		return nil
+	}
+
+	if v.IsZero() {
+		if r.Required {
+			return []FieldError{{Rule: "required", Message: "is required"}}
+		}
+		if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			return nil
+		}
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	res := make([]FieldError, 0)
+
+	var size float64
+	var what string
+	switch {
+	case isNumberKind(v.Kind()):
+		size, what = numberValue(v), "be"
+	case v.Kind() == reflect.String:
+		size, what = float64(utf8.RuneCountInString(v.String())), "have a length of"
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array || v.Kind() == reflect.Map:
+		size, what = float64(v.Len()), "have a length of"
+	}
+
+	if what != "" {
+		if r.Min != nil && size < *r.Min {
+			res = append(res, FieldError{Rule: "min", Message: fmt.Sprintf("must %s at least %v", what, *r.Min)})
+		}
+		if r.Max != nil && size > *r.Max {
+			res = append(res, FieldError{Rule: "max", Message: fmt.Sprintf("must %s at most %v", what, *r.Max)})
+		}
+		if r.Len != nil && size != float64(*r.Len) && what != "be" {
+			res = append(res, FieldError{Rule: "len", Message: fmt.Sprintf("must have a length of %d", *r.Len)})
+		}
+	}
+
+	if r.Enum != nil && !contains(r.Enum, fmt.Sprint(v.Interface())) {
+		res = append(res, FieldError{Rule: "enum", Message: fmt.Sprintf("must be one of [%s]", strings.Join(r.Enum, ", "))})
+	}
+
+	if v.Kind() == reflect.String {
+		if r.Pattern != nil && !r.Pattern.MatchString(v.String()) {
+			res = append(res, FieldError{Rule: "pattern", Message: fmt.Sprintf("must match %s", r.Pattern)})
+		}
+		if r.Format != "" && !formats[r.Format](v.String()) {
+			res = append(res, FieldError{Rule: "format", Message: fmt.Sprintf("must be a valid %s", r.Format)})
+		}
+	}
+
+	return res
+}
+
+func numberValue(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
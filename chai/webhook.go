@@ -0,0 +1,91 @@
+package chai
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// WebhookEvent is an event that is sent to webhook receivers, with the type of its payload.
+type WebhookEvent struct {
+	Name        string
+	Description string
+	Type        reflect.Type
+
+	// File is the Go file the event was registered in, from which the payload type is resolved to be documented.
+	File string
+}
+
+// WebhookRegistry binds the names of the events sent as webhooks to their payload types, so that they can be documented.
+type WebhookRegistry struct {
+	events []WebhookEvent
+}
+
+func NewWebhookRegistry() *WebhookRegistry {
+	return &WebhookRegistry{}
+}
+
+// Events returns the events of the registry in the order they were registered.
+func (wr *WebhookRegistry) Events() []WebhookEvent {
+	if wr == nil {
+		return nil
+	}
+
+	return wr.events
+}
+
+// Webhook is an event whose payloads are of type T. It is sent with a WebhookSender.
+type Webhook[T any] struct {
+	event WebhookEvent
+}
+
+// RegisterWebhook binds the event name to the payload type T and returns the webhook to send the event with.
+func RegisterWebhook[T any](wr *WebhookRegistry, name, description string) Webhook[T] {
+	ev := WebhookEvent{
+		Name:        name,
+		Description: description,
+		Type:        reflect.TypeOf((*T)(nil)).Elem(),
+	}
+
+	if _, file, _, ok := runtime.Caller(1); ok {
+		ev.File = file
+	}
+
+	wr.events = append(wr.events, ev)
+
+	return Webhook[T]{event: ev}
+}
+
+// Event returns the event of the webhook.
+func (wh Webhook[T]) Event() WebhookEvent {
+	return wh.event
+}
+
+// WebhookEventer is implemented by Webhook.
+type WebhookEventer interface {
+	Event() WebhookEvent
+}
+
+// Callback documents the webhooks that an operation subscribes the client to. URL is the runtime expression
+// of the URL they are sent to, e.g. "{$request.body#/callbackUrl}".
+type Callback struct {
+	URL    string
+	Events []WebhookEvent
+}
+
+// Callbacker is implemented by handlers so that the callbacks declared with WithCallback can be documented.
+type Callbacker interface {
+	Callbacks() []Callback
+}
+
+// WithCallback documents that the webhooks are sent to the URL given by the runtime expression url once the operation succeeds,
+// as the callbacks of the operation in OpenAPI 3 documents.
+func WithCallback(url string, webhooks ...WebhookEventer) Option {
+	return func(o *options) {
+		cb := Callback{URL: url}
+		for _, wh := range webhooks {
+			cb.Events = append(cb.Events, wh.Event())
+		}
+
+		o.callbacks = append(o.callbacks, cb)
+	}
+}
@@ -0,0 +1,347 @@
+package chai
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The headers of webhook requests.
+const (
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WebhookStatusError is returned for deliveries whose receiver responded with a non-2xx status code.
+type WebhookStatusError struct {
+	StatusCode int
+}
+
+func (e *WebhookStatusError) Error() string {
+	return fmt.Sprintf("webhook receiver responded with status %d", e.StatusCode)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the sending of an event to a webhook receiver.
+type Delivery struct {
+	ID      string
+	Event   string
+	URL     string
+	Payload []byte
+
+	Status   DeliveryStatus
+	Attempts int
+	// StatusCode is the status code of the response to the last attempt, or 0 if it got no response.
+	StatusCode int
+	// Error is the error of the last attempt, if it failed.
+	Error string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DeliveryStore records deliveries, e.g. to be able to list the failed ones and send them again.
+// Save is called when a delivery is created and after each attempt.
+type DeliveryStore interface {
+	Save(ctx context.Context, d *Delivery) error
+}
+
+// MemoryDeliveryStore keeps the deliveries in memory.
+type MemoryDeliveryStore struct {
+	mu         sync.Mutex
+	ids        []string
+	deliveries map[string]Delivery
+}
+
+func NewMemoryDeliveryStore() *MemoryDeliveryStore {
+	return &MemoryDeliveryStore{deliveries: map[string]Delivery{}}
+}
+
+func (s *MemoryDeliveryStore) Save(ctx context.Context, d *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[d.ID]; !ok {
+		s.ids = append(s.ids, d.ID)
+	}
+	s.deliveries[d.ID] = *d
+
+	return nil
+}
+
+func (s *MemoryDeliveryStore) Get(id string) (Delivery, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[id]
+
+	return d, ok
+}
+
+// List returns the deliveries in the order they were created.
+func (s *MemoryDeliveryStore) List() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Delivery, len(s.ids))
+	for i, id := range s.ids {
+		res[i] = s.deliveries[id]
+	}
+
+	return res
+}
+
+// WebhookSender sends webhooks as JSON POST requests signed with HMAC-SHA256, see SignWebhook.
+// Attempts that fail with a network error, a 408, a 429 or a 5xx response are retried with backoff.
+type WebhookSender struct {
+	secret      []byte
+	client      *http.Client
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	store       DeliveryStore
+}
+
+type WebhookSenderOption func(*WebhookSender)
+
+// NewWebhookSender returns a sender that signs the webhooks with secret. By default, deliveries are attempted
+// up to 5 times with an exponential backoff starting at 1 second, and they are not stored.
+func NewWebhookSender(secret []byte, opts ...WebhookSenderOption) *WebhookSender {
+	s := &WebhookSender{
+		secret:      secret,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 5,
+		backoff:     ExponentialBackoff(time.Second, time.Minute),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func WithWebhookClient(client *http.Client) WebhookSenderOption {
+	return func(s *WebhookSender) {
+		s.client = client
+	}
+}
+
+// WithMaxAttempts sets the number of times a delivery is attempted before it fails.
+func WithMaxAttempts(n int) WebhookSenderOption {
+	return func(s *WebhookSender) {
+		s.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the function that returns how long to wait after the given failed attempt, starting at 1.
+func WithBackoff(backoff func(attempt int) time.Duration) WebhookSenderOption {
+	return func(s *WebhookSender) {
+		s.backoff = backoff
+	}
+}
+
+func WithDeliveryStore(store DeliveryStore) WebhookSenderOption {
+	return func(s *WebhookSender) {
+		s.store = store
+	}
+}
+
+// ExponentialBackoff returns a backoff that waits base after the first attempt and doubles the wait after each attempt, up to max.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			return max
+		}
+
+		return d
+	}
+}
+
+// Send sends payload to url with s, retrying failed attempts. It returns the delivery, and the error of its last attempt if it failed.
+func (wh Webhook[T]) Send(ctx context.Context, s *WebhookSender, url string, payload T) (*Delivery, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Deliver(ctx, &Delivery{ID: newDeliveryID(), Event: wh.event.Name, URL: url, Payload: b})
+}
+
+// Deliver attempts the delivery until it succeeds, fails with an error that is not retried, runs out of attempts or ctx is done.
+// Failed deliveries, e.g. those listed by a DeliveryStore, can be delivered again with a new series of attempts.
+func (s *WebhookSender) Deliver(ctx context.Context, d *Delivery) (*Delivery, error) {
+	now := time.Now()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	d.Status = DeliveryPending
+	d.UpdatedAt = now
+
+	if err := s.save(ctx, d); err != nil {
+		return d, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		d.StatusCode, d.Error = 0, ""
+		d.Attempts++
+
+		err := s.attempt(ctx, d)
+		d.UpdatedAt = time.Now()
+
+		if err == nil {
+			d.Status = DeliverySucceeded
+
+			return d, s.save(ctx, d)
+		}
+
+		d.Error = err.Error()
+
+		if attempt >= s.maxAttempts || !retryable(ctx, d.StatusCode) {
+			d.Status = DeliveryFailed
+			if serr := s.save(ctx, d); serr != nil {
+				return d, serr
+			}
+
+			return d, err
+		}
+
+		if err := s.save(ctx, d); err != nil {
+			return d, err
+		}
+
+		t := time.NewTimer(s.backoff(attempt))
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			d.Status = DeliveryFailed
+			d.Error = ctx.Err().Error()
+			// The delivery is saved even though ctx is done, so that it is not left pending.
+			s.save(context.Background(), d)
+
+			return d, ctx.Err()
+		}
+	}
+}
+
+func (s *WebhookSender) save(ctx context.Context, d *Delivery) error {
+	if s.store == nil {
+		return nil
+	}
+
+	return s.store.Save(ctx, d)
+}
+
+// attempt sends the delivery once, and sets its status code to that of the response.
+func (s *WebhookSender) attempt(ctx context.Context, d *Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, d.ID)
+	req.Header.Set(WebhookEventHeader, d.Event)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(s.secret, timestamp, d.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	d.StatusCode = res.StatusCode
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &WebhookStatusError{StatusCode: res.StatusCode}
+	}
+
+	return nil
+}
+
+// retryable reports whether an attempt that failed with the given status code, or 0 if it got no response, is retried.
+func retryable(ctx context.Context, code int) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	return code == 0 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// SignWebhook returns the signature of a webhook body sent at the given Unix timestamp: "sha256=" followed by the hex-encoded
+// HMAC-SHA256 of the timestamp, a dot and the body.
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks the signature of a webhook request received by r and returns its body.
+// Requests sent more than tolerance ago are rejected, to prevent replays, unless tolerance is 0.
+func VerifyWebhook(secret []byte, r *http.Request, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := r.Header.Get(WebhookTimestampHeader)
+
+	if tolerance > 0 {
+		sec, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+		}
+
+		if d := time.Since(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+			return nil, fmt.Errorf("%w: timestamp is out of tolerance", ErrInvalidSignature)
+		}
+	}
+
+	if !hmac.Equal([]byte(r.Header.Get(WebhookSignatureHeader)), []byte(SignWebhook(secret, timestamp, body))) {
+		return nil, ErrInvalidSignature
+	}
+
+	return body, nil
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,282 @@
+package chai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// DefaultPingInterval is the interval between the pings sent to WebSocket clients when none is set with WithPingInterval.
+const DefaultPingInterval = 30 * time.Second
+
+var (
+	ErrNotWebSocket      = errors.New("the request is not a WebSocket handshake")
+	ErrOriginNotAllowed  = errors.New("the origin of the request is not allowed")
+	ErrHijackUnsupported = errors.New("hijacking is not supported by the response writer")
+	// ErrInvalidMessage is returned by WebSocketConn.Receive for messages that can't be decoded. The connection stays open.
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// WebSocketHandlerFunc handles a WebSocket connection, on which it receives messages of type In and sends messages of type Out as JSON.
+// The connection is closed when the function returns. Errors can't be written once the connection is upgraded,
+// so the returned error is only passed to the error hooks, with the status code it maps to.
+type WebSocketHandlerFunc[Req any, In any, Out any, Err ErrType] func(Req, *WebSocketConn[In, Out], *http.Request) Err
+
+func NewWebSocketHandler[Req any, In any, Out any, Err ErrType](h WebSocketHandlerFunc[Req, In, Out, Err], opts ...Option) *WebSocketHandler[Req, In, Out, Err] {
+	return &WebSocketHandler[Req, In, Out, Err]{
+		f:    h,
+		opts: newOptions(append([]Option{WithoutBody()}, opts...)),
+	}
+}
+
+// WebSocketHandler upgrades requests to WebSocket connections and passes them to its handler function.
+// The request type is filled from the path, query, headers and cookies like with WithoutBody, and errors
+// that occur before the upgrade, such as binding and validation errors, are written by the ErrorWriter.
+//
+// WithMaxBodyBytes limits the size of the received messages, WithPingInterval sets the interval between pings,
+// and WithOriginCheck replaces the default check that the Origin header, if any, matches the Host of the request.
+type WebSocketHandler[Req any, In any, Out any, Err ErrType] struct {
+	f    WebSocketHandlerFunc[Req, In, Out, Err]
+	opts *options
+	req  *Req
+	in   *In
+	out  *Out
+	err  *Err
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer h.opts.recoverPanic(w, r)
+
+	if !isWebSocketHandshake(r) {
+		w.Header().Set("Upgrade", "websocket")
+		h.opts.writeErr(w, r, http.StatusUpgradeRequired, ErrNotWebSocket)
+		return
+	}
+
+	checkOrigin := h.opts.checkOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		h.opts.writeErr(w, r, http.StatusForbidden, ErrOriginNotAllowed)
+		return
+	}
+
+	req, form, code, err := readReq[Req](h.opts, w, r)
+	defer removeForm(form)
+	if err != nil {
+		h.opts.writeErr(w, r, code, err)
+		return
+	}
+
+	if _, ok := w.(http.Hijacker); !ok {
+		h.opts.writeErr(w, r, http.StatusInternalServerError, ErrHijackUnsupported)
+		return
+	}
+
+	websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = int(h.opts.maxBodyBytes)
+
+			conn := newWebSocketConn[In, Out](r.Context(), ws)
+			defer conn.Close()
+
+			interval := h.opts.pingInterval
+			if interval == 0 {
+				interval = DefaultPingInterval
+			}
+			if interval > 0 {
+				go conn.ping(interval)
+			}
+
+			if herr := h.f(*req, conn, r); isErr(herr) {
+				code, err := h.opts.errors.resolve(0, herr)
+
+				h.opts.runErrorHooks(r, code, err)
+			}
+		},
+	}.ServeHTTP(w, r)
+}
+
+// isWebSocketHandshake reports whether r asks to be upgraded to a WebSocket connection.
+func isWebSocketHandshake(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		headerContains(r.Header, "Connection", "upgrade") &&
+		headerContains(r.Header, "Upgrade", "websocket")
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sameOrigin accepts requests without an Origin header, which are not sent by browsers, and requests whose Origin matches their Host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// WithPingInterval sets the interval between the pings sent to WebSocket clients to keep idle connections open
+// and to detect broken ones. A negative interval disables pings.
+func WithPingInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pingInterval = d
+	}
+}
+
+// WithOriginCheck sets the function that accepts or rejects, with 403, the WebSocket handshakes based on their Origin header.
+func WithOriginCheck(fn func(r *http.Request) bool) Option {
+	return func(o *options) {
+		o.checkOrigin = fn
+	}
+}
+
+// WebSocketConn is a WebSocket connection on which messages of type In are received and messages of type Out are sent as JSON text frames.
+// Send and Close can be called concurrently with Receive.
+type WebSocketConn[In any, Out any] struct {
+	ws     *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func newWebSocketConn[In any, Out any](ctx context.Context, ws *websocket.Conn) *WebSocketConn[In, Out] {
+	// Write is only used to send pings, Send writes text frames.
+	ws.PayloadType = websocket.PingFrame
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &WebSocketConn[In, Out]{ws: ws, ctx: ctx, cancel: cancel}
+}
+
+// Receive returns the next message sent by the client. It returns io.EOF once the client closes the connection,
+// and an error wrapping ErrInvalidMessage for messages that can't be decoded, or websocket.ErrFrameTooLarge for messages
+// that exceed the limit set with WithMaxBodyBytes, after which the connection can still be used.
+// Pings are answered while Receive is waiting for a message.
+func (c *WebSocketConn[In, Out]) Receive() (In, error) {
+	var msg In
+
+	var data []byte
+	if err := websocket.Message.Receive(c.ws, &data); err != nil {
+		if err != websocket.ErrFrameTooLarge {
+			c.Close()
+		}
+
+		return msg, err
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	return msg, nil
+}
+
+// Send sends msg to the client.
+func (c *WebSocketConn[In, Out]) Send(msg Out) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := websocket.Message.Send(c.ws, string(b)); err != nil {
+		c.Close()
+
+		return err
+	}
+
+	return nil
+}
+
+// Context returns a context that is done once the connection is closed, or once Receive or Send find it broken or closed by the client.
+func (c *WebSocketConn[In, Out]) Context() context.Context {
+	return c.ctx
+}
+
+// Close sends a close frame to the client and closes the connection.
+func (c *WebSocketConn[In, Out]) Close() error {
+	var err error
+
+	c.once.Do(func() {
+		c.cancel()
+		err = c.ws.Close()
+	})
+
+	return err
+}
+
+// ping sends a ping every interval until the connection is closed. Connections whose pings fail are closed.
+func (c *WebSocketConn[In, Out]) ping(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-t.C:
+			if _, err := c.ws.Write(nil); err != nil {
+				c.Close()
+				return
+			}
+		}
+	}
+}
+
+// WebSocketer is implemented by WebSocket handlers so that their messages can be documented.
+type WebSocketer interface {
+	// Messages returns a nil pointer to the type of the messages the handler receives, and one to the type of those it sends.
+	Messages() (in any, out any)
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) Messages() (any, any) {
+	return h.in, h.out
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) Req() any {
+	return h.req
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) DecodesBody() bool {
+	return false
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) ErrorWriter() ErrorWriter {
+	return h.opts.errorWriter
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) ErrorRegistry() *ErrorRegistry {
+	return h.opts.errors
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) Err() any {
+	return h.err
+}
+
+func (h *WebSocketHandler[Req, In, Out, Err]) Handler() any {
+	return h.f
+}
@@ -2,34 +2,210 @@ package chai
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chi/chi/v5"
 )
 
-func Get[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err]) {
-	r.Method(http.MethodGet, path, chai.NewResHandler(fn))
+// Router is a chi.Router whose chai handlers inherit the options of a Config.
+type Router struct {
+	chi.Router
+	config *chai.Config
 }
 
-func Connect[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err]) {
-	r.Method(http.MethodConnect, path, chai.NewResHandler(fn))
+// NewRouter wraps r so that the handlers registered through the returned Router use the options of config.
+func NewRouter(r chi.Router, config *chai.Config) *Router {
+	return &Router{Router: r, config: config}
 }
 
-func Options[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err]) {
-	r.Method(http.MethodOptions, path, chai.NewResHandler(fn))
+func (r *Router) Config() *chai.Config {
+	return r.config
 }
 
-func Post[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Method(http.MethodPost, path, chai.NewReqResHandler(fn))
+// Route creates a sub-router like chi's Route. The handlers registered on it inherit r's Config.
+func (r *Router) Route(pattern string, fn func(r chi.Router)) chi.Router {
+	sub := r.Router.Route(pattern, func(sub chi.Router) {
+		if fn != nil {
+			fn(&Router{Router: sub, config: r.config})
+		}
+	})
+
+	return &Router{Router: sub, config: r.config}
+}
+
+// Group creates an inline group like chi's Group. The handlers registered on it inherit r's Config.
+func (r *Router) Group(fn func(r chi.Router)) chi.Router {
+	im := r.With()
+	if fn != nil {
+		fn(im)
+	}
+
+	return im
+}
+
+// With adds inline middlewares like chi's With. The handlers registered on the returned router inherit r's Config.
+func (r *Router) With(middlewares ...func(http.Handler) http.Handler) chi.Router {
+	return &Router{Router: r.Router.With(middlewares...), config: r.config}
+}
+
+// Mount mounts h like chi's Mount. A *Router is mounted as its chi.Router so that chi treats it as a sub-router.
+// Its handlers use its own Config, since they are configured when they are registered: create it with NewRouter and r's Config
+// for them to inherit it.
+func (r *Router) Mount(pattern string, h http.Handler) {
+	if sub, ok := h.(*Router); ok {
+		h = sub.Router
+	}
+
+	r.Router.Mount(pattern, h)
+}
+
+// WildcardParam is the name of the path parameter the trailing wildcard of the chi patterns is documented as, e.g. "/static/*"
+// as "/static/{path}". The chai handlers registered through this package read it from the wildcard. The wildcards are left as is if it is empty.
+// The wildcards of the patterns that have a parameter with that name, e.g. "/{path}/*", are documented and read as the "*" parameter instead.
+var WildcardParam = "path"
+
+// wildcardParam returns the name of the path parameter the trailing wildcard of the pattern is documented as: WildcardParam,
+// or "*" if the pattern has a parameter with that name.
+func wildcardParam(pattern string) string {
+	params, _ := openapi2.ParsePathParamsWith(strings.TrimSuffix(pattern, "*"), RegexPatternSchemas)
+	for _, p := range params {
+		if p.Name == WildcardParam {
+			return "*"
+		}
+	}
+
+	return WildcardParam
+}
+
+// Mount mounts h at the pattern like r.Mount. The handlers that are not chi routers, e.g. file servers or third-party handlers,
+// cannot be walked when mounted with r.Mount, so they are not documented. Mount documents them as handling the given methods,
+// GET by default, at the pattern followed by the WildcardParam path parameter. Wrap h with chai.Doc to document its types,
+// or to exclude it with chai.WithoutDocs.
+func Mount(r chi.Router, pattern string, h http.Handler, methods ...string) {
+	if _, ok := h.(chi.Routes); !ok {
+		if len(methods) == 0 {
+			methods = []string{http.MethodGet}
+		}
+
+		h = &mountedHandler{Handler: h, methods: methods}
+	}
+
+	r.Mount(pattern, h)
+}
+
+// mountedHandler is a handler mounted with Mount. It is a chi.Routes with a single wildcard route, which chi.Walk walks.
+type mountedHandler struct {
+	http.Handler
+	methods []string
+}
+
+func (m *mountedHandler) Routes() []chi.Route {
+	handlers := make(map[string]http.Handler, len(m.methods))
+	for _, method := range m.methods {
+		handlers[method] = &mountedRoute{Handler: m.Handler}
+	}
+
+	return []chi.Route{{Pattern: "/*", Handlers: handlers}}
+}
+
+func (m *mountedHandler) Middlewares() chi.Middlewares {
+	return nil
+}
+
+func (m *mountedHandler) Match(rctx *chi.Context, method, path string) bool {
+	return true
+}
+
+// mountedRoute is the handler of the route of a mountedHandler as chi.Walk walks it, so that getChiRoutes recognizes the handlers
+// mounted with Mount. chi serves the mountedHandler itself, so it is only walked.
+type mountedRoute struct {
+	http.Handler
+}
+
+// handlerOptions returns the options of a handler registered on r: chi's path params, then r's Config, then opts and extra.
+func handlerOptions(r chai.Methoder, opts []chai.Option, extra ...chai.Option) []chai.Option {
+	res := []chai.Option{chai.WithPathParamFunc(pathParam), chai.WithConfig(chai.ConfigOf(r))}
+	res = append(res, opts...)
+
+	return append(res, extra...)
+}
+
+func Get[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Head[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodHead, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Connect[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodConnect, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
 }
 
-func Put[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Method(http.MethodPut, path, chai.NewReqResHandler(fn))
+func Options[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodOptions, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
 }
 
-func Patch[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Method(http.MethodPatch, path, chai.NewReqResHandler(fn))
+func Post[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPost, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
 }
 
-func Delete[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Method(http.MethodDelete, path, chai.NewReqResHandler(fn))
+func Put[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPut, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Patch[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPatch, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Delete[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodDelete, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+// GetReq registers a GET handler whose request type is filled only from the path, query, headers and cookies.
+func GetReq[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// HeadReq registers a HEAD handler whose request type is filled only from the path, query, headers and cookies.
+func HeadReq[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodHead, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// DeleteReq registers a DELETE handler whose request type is filled only from the path, query, headers and cookies.
+func DeleteReq[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodDelete, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// SSE registers a GET handler that streams Server-Sent Events. Its request type is filled only from the path, query, headers and cookies.
+func SSE[Req any, T any, Err chai.ErrType](r chai.Methoder, path string, fn chai.SSEHandlerFunc[Req, T, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewSSEHandler(fn, handlerOptions(r, opts)...))
+}
+
+// Stream registers a GET handler that streams its items as application/x-ndjson or a JSON array. Its request type is filled only from the path, query, headers and cookies.
+func Stream[Req any, T any, Err chai.ErrType](r chai.Methoder, path string, fn chai.StreamHandlerFunc[Req, T, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewStreamHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// WebSocket registers a GET handler that upgrades the requests to WebSocket connections on which messages of type In are received
+// and messages of type Out are sent as JSON. Its request type is filled only from the path, query, headers and cookies.
+func WebSocket[Req any, In any, Out any, Err chai.ErrType](r chai.Methoder, path string, fn chai.WebSocketHandlerFunc[Req, In, Out, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewWebSocketHandler(fn, handlerOptions(r, opts)...))
+}
+
+// Upload registers a POST handler whose request type is decoded from a multipart/form-data body, see chai.MultipartFields.
+func Upload[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPost, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithMultipart())...))
+}
+
+// pathParam returns chi's path param, and the wildcard for WildcardParam unless the pattern has a parameter with that name,
+// like ParsePathParams documents them.
+func pathParam(r *http.Request, name string) string {
+	if rctx := chi.RouteContext(r.Context()); name != "" && name == WildcardParam && rctx != nil && wildcardParam(rctx.RoutePattern()) == name {
+		return chi.URLParam(r, "*")
+	}
+
+	return chi.URLParam(r, name)
 }
@@ -0,0 +1,69 @@
+package chai_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	chai "github.com/go-chai/chai/chi"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadRequest struct {
+	File *multipart.FileHeader `form:"file"`
+}
+
+func TestUploadRemovesTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	r := chi.NewRouter()
+
+	chai.Upload(r, "/files", func(req *uploadRequest, w http.ResponseWriter, r *http.Request) (int64, int, error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		if len(entries) == 0 {
+			return 0, http.StatusInternalServerError, os.ErrNotExist
+		}
+
+		return req.File.Size, http.StatusOK, nil
+	})
+
+	// Files larger than the memory limit of multipart forms are stored in temporary files.
+	const size = 33 << 20
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "big.bin")
+	require.NoError(t, err)
+	_, err = io.CopyN(fw, zeros{}, size)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/files", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, strconv.Itoa(size)+"\n", w.Body.String())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+type zeros struct{}
+
+func (zeros) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
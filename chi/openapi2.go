@@ -1,43 +1,52 @@
 package chai
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/go-chai/chai/chai"
 	"github.com/go-chai/chai/openapi2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-openapi/spec"
 )
 
-var integerSchema = spec.SimpleSchema{Type: "integer"}
-var numberSchema = spec.SimpleSchema{Type: "number"}
+// RegexPatternSchemas are the types of the path parameters of the chi patterns whose regular expressions match them, e.g. {id:^[0-9]+$}.
+// It is the same map as openapi2.RegexPatternSchemas, which the gorilla patterns are typed with, so the types added to either are
+// shared by both routers.
+var RegexPatternSchemas = openapi2.RegexPatternSchemas
 
-var RegexPatternSchemas = map[string]spec.SimpleSchema{
-	"/^(0|-*[1-9]+[0-9]*)$/":  integerSchema,
-	"^[0-9]+$":                integerSchema,
-	"[+-]?([0-9]*[.])?[0-9]+": numberSchema,
-}
-
-func OpenAPI2(r chi.Routes) (*spec.Swagger, error) {
+func OpenAPI2(r chi.Routes, opts ...openapi2.Option) (*spec.Swagger, error) {
 	routes, err := getChiRoutes(r)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return openapi2.Docs(routes)
+	return openapi2.Docs(routes, opts...)
 }
 
+// getChiRoutes returns the routes of r. The handlers mounted with chi's Mount that are not chi routers cannot be walked,
+// and chi registers them at the pattern followed by a wildcard with a handler of its own. So the wildcard routes must have
+// chai handlers, or handlers mounted with Mount, and an error is returned for the others.
 func getChiRoutes(r chi.Routes) ([]*openapi2.Route, error) {
 	routes := make([]*openapi2.Route, 0)
 
 	err := chi.Walk(r, func(method, path string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if m, ok := handler.(*mountedRoute); ok {
+			handler = m.Handler
+		} else if _, ok := handler.(chai.Handlerer); !ok && strings.HasSuffix(path, "*") {
+			return fmt.Errorf("the handler of %s %s is not a chai handler: mount the handlers that are not chi routers with Mount, "+
+				"and wrap the others with chai.Doc", method, path)
+		}
+
 		params, regexlessPath := ParsePathParams(path)
 		routes = append(routes, &openapi2.Route{
-			Method:  method,
-			Path:    regexlessPath,
-			Params:  params,
-			Handler: handler,
+			Method:      method,
+			Path:        regexlessPath,
+			Params:      params,
+			Handler:     handler,
+			Middlewares: middlewares,
 		})
 
 		return nil
@@ -50,80 +59,16 @@ func getChiRoutes(r chi.Routes) ([]*openapi2.Route, error) {
 	return routes, nil
 }
 
+// ParsePathParams parses the parameters of a chi pattern, see openapi2.ParsePathParams, typed with RegexPatternSchemas.
+// The trailing wildcard of the pattern is documented as the WildcardParam path parameter, e.g. "/static/*" as "/static/{path}",
+// or as the "*" path parameter if the pattern has a parameter with that name, e.g. "/{path}/*" as "/{path}/{*}".
 func ParsePathParams(path string) ([]spec.Parameter, string) {
-	res := make([]spec.Parameter, 0)
-	regexlessPath := ""
-
-	for {
-		param, before, after := nextParam(path)
-		regexlessPath += before
-
-		if param == nil {
-			break
-		}
-
-		regexlessPath += "{" + param.Name + "}"
-
-		res = append(res, *param)
-		path = after
-	}
-
-	return res, regexlessPath
-}
-
-func nextParam(pattern string) (param *spec.Parameter, before string, after string) {
-	before, after, found := strings.Cut(pattern, "{")
-	if !found {
-		return nil, before, after
+	if WildcardParam == "" || !strings.HasSuffix(path, "*") {
+		return openapi2.ParsePathParamsWith(path, RegexPatternSchemas)
 	}
 
-	// Read to closing } taking into account opens and closes in curl count (cc)
-	cc := 1
-	pe := 0
-
-	for i, c := range after {
-		if c == '{' {
-			cc++
-		} else if c == '}' {
-			cc--
-
-			if cc == 0 {
-				pe = i
-				break
-			}
-		}
-	}
-
-	key := after[:pe]
-	after = after[pe+1:]
-
-	key, rexpat, _ := strings.Cut(key, ":")
-
-	if len(rexpat) > 0 {
-		if rexpat[0] != '^' {
-			rexpat = "^" + rexpat
-		}
-		if rexpat[len(rexpat)-1] != '$' {
-			rexpat += "$"
-		}
-	}
-
-	schema, ok := RegexPatternSchemas[rexpat]
-	if !ok {
-		schema = spec.SimpleSchema{
-			Type: "string",
-		}
-	}
+	params, path := openapi2.ParsePathParamsWith(strings.TrimSuffix(path, "*")+"{"+wildcardParam(path)+"}", RegexPatternSchemas)
+	params[len(params)-1].Description = "The rest of the path"
 
-	return &spec.Parameter{
-		CommonValidations: spec.CommonValidations{
-			Pattern: rexpat,
-		},
-		ParamProps: spec.ParamProps{
-			Name:     key,
-			In:       "path",
-			Required: true,
-		},
-		SimpleSchema: schema,
-	}, before, after
+	return params, path
 }
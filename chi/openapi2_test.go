@@ -1,12 +1,17 @@
 package chai_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	chaicore "github.com/go-chai/chai/chai"
 	chai "github.com/go-chai/chai/chi"
 	"github.com/go-chai/chai/examples/shared/controller"
 	"github.com/go-chai/chai/internal/tests"
+	"github.com/go-chai/chai/openapi2"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +32,39 @@ func TestOpenAPI2(t *testing.T) {
 			},
 			filePath: "testdata/celler.json",
 		},
+		{
+			name: "mount",
+			args: args{
+				r: func() chi.Routes {
+					r := chi.NewRouter()
+
+					// @Summary  Show a file
+					// @Tags     files
+					chai.Get(r, "/files/*", func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+						return nil, 0, nil
+					})
+
+					chai.Mount(r, "/assets", chaicore.Doc(http.FileServer(http.Dir("testdata")), chaicore.WithResponseType((*string)(nil))))
+					chai.Mount(r, "/hidden", chaicore.Doc(http.NotFoundHandler(), chaicore.WithoutDocs()))
+
+					// @Summary  Show a file of a directory
+					// @Tags     files
+					chai.GetReq(r, "/dirs/{path}/*", func(req *testDirRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+						return nil, 0, nil
+					})
+
+					// @Summary  Delete an item
+					// @Tags     items
+					// @Success  204
+					r.Method(http.MethodDelete, "/items/{id}", chaicore.Doc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusNoContent)
+					}), chaicore.WithRequestType((*tests.TestParamsOnlyRequest)(nil))))
+
+					return r
+				}(),
+			},
+			filePath: "testdata/mount.json",
+		},
 	}
 	for _, tt := range tcs {
 		t.Run(tt.name, func(t *testing.T) {
@@ -36,3 +74,119 @@ func TestOpenAPI2(t *testing.T) {
 		})
 	}
 }
+
+type testDirRequest struct {
+	Dir  string `path:"path"`
+	Rest string `path:"*"`
+}
+
+func TestMount(t *testing.T) {
+	r := chi.NewRouter()
+
+	chai.Get(r, "/files/*", func(w http.ResponseWriter, r *http.Request) (*string, int, error) {
+		path := chi.URLParam(r, "*")
+		return &path, http.StatusOK, nil
+	})
+	chai.Mount(r, "/assets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	chai.GetReq(r, "/dirs/{path}/*", func(req *testDirRequest, w http.ResponseWriter, r *http.Request) (*string, int, error) {
+		res := req.Dir + ":" + req.Rest
+		return &res, http.StatusOK, nil
+	})
+
+	tcs := []struct {
+		path string
+		want string
+	}{
+		{path: "/files/a/b.txt", want: "\"a/b.txt\"\n"},
+		{path: "/assets/c.css", want: "/assets/c.css"},
+		{path: "/dirs/a/b/c.txt", want: "\"a:b/c.txt\"\n"},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
+
+func TestRegexPatternSchemas(t *testing.T) {
+	const objectID = "^[a-f0-9]{24}$"
+
+	defer delete(chai.RegexPatternSchemas, objectID)
+
+	chai.RegexPatternSchemas[objectID] = spec.SimpleSchema{Type: "string", Format: "objectid"}
+
+	tcs := []struct {
+		name       string
+		parse      func(string) ([]spec.Parameter, string)
+		path       string
+		wantType   string
+		wantFormat string
+	}{
+		{name: "chi", parse: chai.ParsePathParams, path: "/accounts/{id:^[a-f0-9]{24}$}", wantType: "string", wantFormat: "objectid"},
+		{name: "chi integer", parse: chai.ParsePathParams, path: "/accounts/{id:^[0-9]+$}", wantType: "integer"},
+		{name: "openapi2", parse: openapi2.ParsePathParams, path: "/accounts/{id:^[a-f0-9]{24}$}", wantType: "string", wantFormat: "objectid"},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			params, path := tt.parse(tt.path)
+			require.Equal(t, "/accounts/{id}", path)
+			require.Len(t, params, 1)
+			require.Equal(t, tt.wantType, params[0].Type)
+			require.Equal(t, tt.wantFormat, params[0].Format)
+		})
+	}
+}
+
+func TestWildcardRouteHandlers(t *testing.T) {
+	tcs := []struct {
+		name    string
+		mount   func(r chi.Router)
+		wantErr bool
+	}{
+		{
+			name: "chi's Mount",
+			mount: func(r chi.Router) {
+				r.Mount("/legacy", http.NotFoundHandler())
+			},
+			wantErr: true,
+		},
+		{
+			name: "plain handler",
+			mount: func(r chi.Router) {
+				r.Get("/legacy/*", http.NotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Mount",
+			mount: func(r chi.Router) {
+				chai.Mount(r, "/legacy", http.NotFoundHandler())
+			},
+		},
+		{
+			name: "chi router",
+			mount: func(r chi.Router) {
+				r.Mount("/legacy", chi.NewRouter())
+			},
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			tt.mount(r)
+
+			_, err := chai.OpenAPI2(r, openapi2.WithReflection())
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "/legacy/*")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package chai
+
+import (
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/chai/openapi31"
+	"github.com/go-chi/chi/v5"
+)
+
+// OpenAPI31 documents the routes of r in an OpenAPI 3.1 document.
+func OpenAPI31(r chi.Routes, opts ...openapi2.Option) (*openapi31.Document, error) {
+	routes, err := getChiRoutes(r)
+	if err != nil {
+		return nil, err
+	}
+	return openapi31.Docs(routes, opts...)
+}
@@ -0,0 +1,105 @@
+package chai_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chai/chai/chai"
+	chaichi "github.com/go-chai/chai/chi"
+	"github.com/go-chai/chai/internal/tests"
+	"github.com/go-chai/chai/openapi31"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI31(t *testing.T) {
+	type args struct {
+		r        chi.Routes
+		webhooks *chai.WebhookRegistry
+	}
+	tcs := []struct {
+		name     string
+		args     args
+		filePath string
+	}{
+		{
+			name: "t1",
+			args: args{
+				r: func() chi.Routes {
+					r := chi.NewRouter()
+
+					// @Summary  Show a nullable response
+					// @Tags     nullable
+					chaichi.GetReq(r, "/nullable/{id}", func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestNullableResponse, int, error) {
+						return nil, 0, nil
+					})
+
+					return r
+				}(),
+			},
+			filePath: "testdata/openapi31_t1.json",
+		},
+		{
+			name: "t2",
+			args: args{
+				r: func() chi.Routes {
+					r := chi.NewRouter()
+
+					chaichi.Post(r, "/events", func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+						return nil, 0, nil
+					}, chai.WithCallback("{$request.body#/callbackUrl}", testEventCreated))
+
+					return r
+				}(),
+				webhooks: testWebhooks,
+			},
+			filePath: "testdata/openapi31_t2.json",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chaichi.OpenAPI31(tt.args.r)
+			require.NoError(t, err)
+
+			if tt.args.webhooks != nil {
+				require.NoError(t, openapi31.AddWebhooks(got, tt.args.webhooks))
+			}
+
+			require.JSONEq(t, tests.LoadFile(t, tt.filePath), tests.JS(got))
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	r := chi.NewRouter()
+	chaichi.Get(r, "/responses", func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+		return nil, 0, nil
+	})
+
+	docs, err := chaichi.OpenAPI31(r)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"bar": {"type": "string"},
+			"foo": {"type": "string"},
+			"test_inner_response": {"$ref": "#/$defs/tests.TestInnerResponse"}
+		},
+		"$defs": {
+			"tests.TestInnerResponse": {
+				"type": "object",
+				"properties": {
+					"bar_bar": {"type": "integer"},
+					"foo_foo": {"type": "integer"}
+				}
+			}
+		}
+	}`, tests.JS(docs.JSONSchema("tests.TestResponse")))
+	require.Nil(t, docs.JSONSchema("tests.Missing"))
+}
+
+var testWebhooks = chai.NewWebhookRegistry()
+
+var testEventCreated = chai.RegisterWebhook[*tests.TestEvent](testWebhooks, "event.created", "Sent when an event is created")
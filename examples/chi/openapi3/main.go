@@ -7,6 +7,7 @@ import (
 
 	"github.com/ghodss/yaml"
 
+	chaicore "github.com/go-chai/chai/chai"
 	chai "github.com/go-chai/chai/chi"
 	"github.com/go-chai/chai/examples/shared/controller"
 
@@ -29,16 +30,17 @@ func main() {
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/accounts", func(r chi.Router) {
-			chai.Get(r, "/{id}", c.ShowAccount)
+			chai.GetReq(r, "/{id}", c.ShowAccount)
 			chai.Get(r, "/", c.ListAccounts)
+			chai.Stream(r, "/export", c.ExportAccounts)
 			chai.Post(r, "/", c.AddAccount)
-			r.Delete("/{id:[0-9]+}", c.DeleteAccount)
-			r.Patch("/{id}", c.UpdateAccount)
-			r.Post("/{id}/images", c.UploadAccountImage)
+			r.Method(http.MethodDelete, "/{id:[0-9]+}", chaicore.Doc(http.HandlerFunc(c.DeleteAccount)))
+			r.Method(http.MethodPatch, "/{id}", chaicore.Doc(http.HandlerFunc(c.UpdateAccount)))
+			chai.Upload(r, "/{id}/images", c.UploadAccountImage)
 		})
 
 		r.Route("/bottles", func(r chi.Router) {
-			chai.Get(r, "/{id}", c.ShowBottle)
+			chai.GetReq(r, "/{id}", c.ShowBottle)
 			chai.Get(r, "/", c.ListBottles)
 		})
 
@@ -52,7 +54,7 @@ func main() {
 			chai.Get(r, "/ping", c.PingExample)
 			chai.Get(r, "/calc", c.CalcExample)
 			// chai.Get(r, "/group{s/{gro}up_id}/accounts/{account_id}", c.PathParamsExample)
-			chai.Get(r, "/groups/{group_id}/accounts/{account_id}", c.PathParamsExample)
+			chai.GetReq(r, "/groups/{group_id}/accounts/{account_id}", c.PathParamsExample)
 			chai.Get(r, "/header", c.HeaderExample)
 			chai.Get(r, "/securities", c.SecuritiesExample)
 			chai.Get(r, "/attribute", c.AttributeExample)
@@ -68,7 +68,7 @@ var doc = `{
             "model.Account2": {
                 "properties": {
                     "custom_type_4567": {
-                        "description": "zzz",
+                        "description": "custom type 4567 description",
                         "items": {
                             "$ref": "#/components/schemas/model.CustomType456"
                         },
@@ -107,6 +107,9 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "required": [
+                    "name"
+                ],
                 "type": "object"
             },
             "model.Admin": {
@@ -312,8 +315,7 @@ var doc = `{
                         }
                     },
                     "description": "Add account",
-                    "required": true,
-                    "x-originalParamName": "account"
+                    "required": true
                 },
                 "responses": {
                     "200": {
@@ -346,6 +348,16 @@ var doc = `{
                         },
                         "description": "Not Found"
                     },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/httputil.Error"
+                                }
+                            }
+                        },
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "content": {
                             "application/json": {
@@ -363,6 +375,52 @@ var doc = `{
                 ]
             }
         },
+        "/api/v1/accounts/export": {
+            "get": {
+                "description": "stream all accounts, one per line",
+                "parameters": [
+                    {
+                        "description": "name search by q",
+                        "in": "query",
+                        "name": "q",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/model.Account"
+                                }
+                            },
+                            "application/x-ndjson": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/model.Account"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/httputil.Error"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Export accounts",
+                "tags": [
+                    "accounts"
+                ]
+            }
+        },
         "/api/v1/accounts/{id}": {
             "delete": {
                 "description": "Delete by account ID",
@@ -506,8 +564,7 @@ var doc = `{
                         }
                     },
                     "description": "Update account",
-                    "required": true,
-                    "x-originalParamName": "account"
+                    "required": true
                 },
                 "responses": {
                     "200": {
@@ -579,11 +636,7 @@ var doc = `{
                                     "file": {
                                         "description": "account image",
                                         "format": "binary",
-                                        "required": [
-                                            "file"
-                                        ],
-                                        "type": "string",
-                                        "x-formData-name": "file"
+                                        "type": "string"
                                     }
                                 },
                                 "required": [
@@ -592,7 +645,8 @@ var doc = `{
                                 "type": "object"
                             }
                         }
-                    }
+                    },
+                    "required": true
                 },
                 "responses": {
                     "200": {
@@ -625,6 +679,16 @@ var doc = `{
                         },
                         "description": "Not Found"
                     },
+                    "413": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/httputil.Error"
+                                }
+                            }
+                        },
+                        "description": "Request Entity Too Large"
+                    },
                     "500": {
                         "content": {
                             "application/json": {
@@ -648,12 +712,9 @@ var doc = `{
                 "requestBody": {
                     "content": {
                         "application/json": {
-                            "schema": {
-                                "type": "object"
-                            }
+                            "schema": {}
                         }
-                    },
-                    "x-originalParamName": "body"
+                    }
                 },
                 "responses": {
                     "200": {
@@ -991,8 +1052,7 @@ var doc = `{
                                 "$ref": "#/components/schemas/model.Account"
                             }
                         }
-                    },
-                    "x-originalParamName": "body"
+                    }
                 },
                 "responses": {
                     "200": {
@@ -8,6 +8,7 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/gorilla/mux"
 
+	chaicore "github.com/go-chai/chai/chai"
 	_ "github.com/go-chai/chai/examples/docs/celler" // This is required to be able to serve the stored swagger spec in prod
 	"github.com/go-chai/chai/examples/shared/controller"
 	"github.com/go-chai/chai/examples/shared/httputil"
@@ -22,13 +23,14 @@ func main() {
 
 	c := controller.NewController()
 
-	chai.Get(r, "/api/v1/accounts/{id}", c.ShowAccount)
+	chai.Stream(r, "/api/v1/accounts/export", c.ExportAccounts)
+	chai.GetReq(r, "/api/v1/accounts/{id}", c.ShowAccount)
 	chai.Get(r, "/api/v1/accounts/", c.ListAccounts)
 	chai.Post(r, "/api/v1/accounts/", c.AddAccount)
-	r.HandleFunc("/api/v1/accounts/{id}", c.DeleteAccount).Methods(http.MethodDelete)
-	r.HandleFunc("/api/v1/accounts/{id}", c.UpdateAccount).Methods(http.MethodPatch)
-	r.HandleFunc("/api/v1/accounts/{id}/images", c.UploadAccountImage).Methods(http.MethodPost)
-	chai.Get(r, "/api/v1/bottles/{id}", c.ShowBottle)
+	r.Handle("/api/v1/accounts/{id}", chaicore.Doc(http.HandlerFunc(c.DeleteAccount))).Methods(http.MethodDelete)
+	r.Handle("/api/v1/accounts/{id}", chaicore.Doc(http.HandlerFunc(c.UpdateAccount))).Methods(http.MethodPatch)
+	chai.Upload(r, "/api/v1/accounts/{id}/images", c.UploadAccountImage)
+	chai.GetReq(r, "/api/v1/bottles/{id}", c.ShowBottle)
 	chai.Get(r, "/api/v1/bottles/", c.ListBottles)
 	chai.Get(r, "/api/v1/bottles/", c.ListBottles)
 
@@ -40,7 +42,7 @@ func main() {
 	chai.Get(r, "/api/v1/examples/ping", c.PingExample)
 	chai.Get(r, "/api/v1/examples/calc", c.CalcExample)
 	// chai.Get(r, "/api/v1/examples/group{s/{gro}up_id}/accounts/{account_id}", c.CalcExample)
-	chai.Get(r, "/api/v1/examples/groups/{group_id}/accounts/{account_id}", c.PathParamsExample)
+	chai.GetReq(r, "/api/v1/examples/groups/{group_id}/accounts/{account_id}", c.PathParamsExample)
 	chai.Get(r, "/api/v1/examples/header", c.HeaderExample)
 	chai.Get(r, "/api/v1/examples/securities", c.SecuritiesExample)
 	chai.Get(r, "/api/v1/examples/attribute", c.AttributeExample)
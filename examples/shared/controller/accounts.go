@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 
@@ -17,18 +18,12 @@ import (
 // @Tags         accounts
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Account ID"
 // @Success      200  {object}  model.Account
 // @Failure      400  {object}  httputil.Error
 // @Failure      404  {object}  httputil.Error
 // @Failure      500  {object}  httputil.Error
-func (c *Controller) ShowAccount(w http.ResponseWriter, r *http.Request) (*model.Account, int, error) {
-	id := chi.URLParam(r, "id")
-	aid, err := strconv.Atoi(id)
-	if err != nil {
-		return nil, http.StatusBadRequest, err
-	}
-	account, err := model.AccountOne(aid)
+func (c *Controller) ShowAccount(req *ShowAccountRequest, w http.ResponseWriter, r *http.Request) (*model.Account, int, error) {
+	account, err := model.AccountOne(req.ID)
 	if err != nil {
 		return nil, http.StatusNotFound, err
 	}
@@ -36,6 +31,11 @@ func (c *Controller) ShowAccount(w http.ResponseWriter, r *http.Request) (*model
 	return &account, http.StatusOK, nil
 }
 
+// ShowAccountRequest example
+type ShowAccountRequest struct {
+	ID int `path:"id" json:"-" description:"Account ID"`
+}
+
 // ListAccounts godoc
 // @Summary      List accounts
 // @Description  get accounts
@@ -60,6 +60,39 @@ func (c *Controller) ListAccounts(w http.ResponseWriter, r *http.Request) (*[]mo
 	return &accounts, http.StatusOK, nil
 }
 
+// ExportAccounts godoc
+// @Summary      Export accounts
+// @Description  stream all accounts, one per line
+// @Tags         accounts
+// @Success      200  {object}  model.Account
+// @Failure      500  {object}  httputil.Error
+// @Router       /accounts/export [get]
+func (c *Controller) ExportAccounts(req *ExportAccountsRequest, w http.ResponseWriter, r *http.Request) (<-chan model.Account, int, error) {
+	accounts, err := model.AccountsAll(req.Q)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	ch := make(chan model.Account)
+	go func() {
+		defer close(ch)
+		for _, a := range accounts {
+			select {
+			case ch <- a:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	return ch, http.StatusOK, nil
+}
+
+// ExportAccountsRequest example
+type ExportAccountsRequest struct {
+	Q string `query:"q" description:"name search by q"`
+}
+
 // AddAccount godoc
 // @Summary      Add an account
 // @Description  add by json account
@@ -70,12 +103,10 @@ func (c *Controller) ListAccounts(w http.ResponseWriter, r *http.Request) (*[]mo
 // @Success      200      {object}  model.Account
 // @Failure      400      {object}  httputil.Error
 // @Failure      404      {object}  httputil.Error
+// @Failure      422      {object}  httputil.Error
 // @Failure      500      {object}  httputil.Error
 // @Router       /accounts [post]
 func (c *Controller) AddAccount(addAccount *model.AddAccount, w http.ResponseWriter, r *http.Request) (*model.Account, int, error) {
-	if err := addAccount.Validation(); err != nil {
-		return nil, http.StatusBadRequest, err
-	}
 	account := model.Account{
 		Name: addAccount.Name,
 	}
@@ -151,25 +182,20 @@ func (c *Controller) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 // @Summary      Upload account image
 // @Description  Upload file
 // @Tags         accounts
-// @Accept       multipart/form-data
 // @Produce      json
-// @Param        id    path      int   true  "Account ID"
-// @Param        file  formData  file  true  "account image"
 // @Success      200   {object}  Message
 // @Failure      400   {object}  httputil.Error
 // @Failure      404   {object}  httputil.Error
+// @Failure      413   {object}  httputil.Error
 // @Failure      500   {object}  httputil.Error
-func (c *Controller) UploadAccountImage(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if httputil.NewError(w, http.StatusBadRequest, err) {
-		return
-	}
-	_, h, err := r.FormFile("file")
-	if httputil.NewError(w, http.StatusBadRequest, err) {
-		return
-	}
+func (c *Controller) UploadAccountImage(req *UploadAccountImageRequest, w http.ResponseWriter, r *http.Request) (*Message, int, error) {
+	return &Message{Message: fmt.Sprintf("upload complete userID=%d filename=%s", req.ID, req.File.Filename)}, http.StatusOK, nil
+}
 
-	httputil.Respond(w, r, Message{Message: fmt.Sprintf("upload complete userID=%d filename=%s", id, h.Filename)})
+// UploadAccountImageRequest example
+type UploadAccountImageRequest struct {
+	ID   int                   `path:"id" json:"-" description:"Account ID"`
+	File *multipart.FileHeader `form:"file" maxSize:"10485760" validate:"required" description:"account image"`
 }
 
 // Admin example
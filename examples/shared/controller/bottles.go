@@ -2,9 +2,6 @@ package controller
 
 import (
 	"net/http"
-	"strconv"
-
-	"github.com/go-chi/chi/v5"
 
 	"github.com/go-chai/chai/examples/shared/httputil"
 	"github.com/go-chai/chai/examples/shared/model"
@@ -17,24 +14,23 @@ import (
 // @Tags         bottles
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Bottle ID"
 // @Success      200  {object}  model.Bottle
 // @Failure      400  {object}  httputil.Error
 // @Failure      404  {object}  httputil.Error
 // @Failure      500  {object}  httputil.Error
-func (c *Controller) ShowBottle(w http.ResponseWriter, r *http.Request) (*model.Bottle, int, error) {
-	id := chi.URLParam(r, "id")
-	bid, err := strconv.Atoi(id)
-	if err != nil {
-		return nil, http.StatusBadRequest, err
-	}
-	bottle, err := model.BottleOne(bid)
+func (c *Controller) ShowBottle(req *ShowBottleRequest, w http.ResponseWriter, r *http.Request) (*model.Bottle, int, error) {
+	bottle, err := model.BottleOne(req.ID)
 	if err != nil {
 		return nil, http.StatusNotFound, err
 	}
 	return bottle, http.StatusOK, nil
 }
 
+// ShowBottleRequest example
+type ShowBottleRequest struct {
+	ID int `path:"id" json:"-" description:"Bottle ID"`
+}
+
 // ListBottles godoc
 // @Summary      List bottles
 // @Description  get bottles
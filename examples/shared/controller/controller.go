@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	chaicore "github.com/go-chai/chai/chai"
 	chai "github.com/go-chai/chai/chi"
 	"github.com/go-chai/chai/examples/shared/httputil"
 	"github.com/go-chai/chai/examples/shared/model"
@@ -25,12 +26,13 @@ func (c *Controller) ChiRoutes() chi.Router {
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/accounts", func(r chi.Router) {
-			chai.Get(r, "/{id}", c.ShowAccount)
+			chai.GetReq(r, "/{id}", c.ShowAccount)
 			chai.Get(r, "/", c.ListAccounts)
+			chai.Stream(r, "/export", c.ExportAccounts)
 			chai.Post(r, "/", c.AddAccount)
-			r.Delete("/{id:[0-9]+}", c.DeleteAccount)
-			r.Patch("/{id}", c.UpdateAccount)
-			r.Post("/{id}/images", c.UploadAccountImage)
+			r.Method(http.MethodDelete, "/{id:[0-9]+}", chaicore.Doc(http.HandlerFunc(c.DeleteAccount)))
+			r.Method(http.MethodPatch, "/{id}", chaicore.Doc(http.HandlerFunc(c.UpdateAccount)))
+			chai.Upload(r, "/{id}/images", c.UploadAccountImage)
 		})
 
 		r.Route("/bottles", func(r chi.Router) {
@@ -71,7 +73,7 @@ func (c *Controller) ChiRoutes() chi.Router {
 			chai.Get(r, "/ping", c.PingExample)
 			chai.Get(r, "/calc", c.CalcExample)
 			// chai.Get(r, "/group{s/{gro}up_id}/accounts/{account_id}", c.PathParamsExample)
-			chai.Get(r, "/groups/{group_id}/accounts/{account_id}", c.PathParamsExample)
+			chai.GetReq(r, "/groups/{group_id}/accounts/{account_id}", c.PathParamsExample)
 			chai.Get(r, "/header", c.HeaderExample)
 			chai.Get(r, "/securities", c.SecuritiesExample)
 			chai.Get(r, "/attribute", c.AttributeExample)
@@ -82,7 +84,8 @@ func (c *Controller) ChiRoutes() chi.Router {
 	return r
 }
 
-func auth(next http.Handler) http.Handler {
+// auth rejects the requests without an Authorization header, and is documented so that the operations behind it are.
+var auth = chaicore.DocumentMiddleware(func(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if len(r.Header.Get("Authorization")) == 0 {
 			httputil.NewError(w, http.StatusUnauthorized, errors.New("Authorization is required Header"))
@@ -90,7 +93,11 @@ func auth(next http.Handler) http.Handler {
 		}
 		next.ServeHTTP(w, r)
 	})
-}
+}, chaicore.MiddlewareDocs{
+	Headers:   []chaicore.MiddlewareHeader{{Name: "Authorization", Description: "The API key", Required: true}},
+	Security:  []map[string][]string{{"ApiKeyAuth": {}}},
+	Responses: map[int]string{http.StatusUnauthorized: "Unauthorized"},
+})
 
 // Message example
 type Message struct {
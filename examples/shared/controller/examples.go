@@ -7,7 +7,6 @@ import (
 
 	"github.com/go-chai/chai/examples/shared/httputil"
 	"github.com/go-chai/chai/examples/shared/model"
-	"github.com/go-chi/chi/v5"
 )
 
 // PingExample godoc
@@ -55,23 +54,18 @@ func (c *Controller) CalcExample(w http.ResponseWriter, r *http.Request) (string
 // @Tags         example
 // @Accept       json
 // @Produce      json
-// @Param        group_id    path      int     true  "Group ID"
-// @Param        account_id  path      int     true  "Account ID"
 // @Success      200         {string}  string  "answer"
 // @Failure      400         {string}  string  "ok"
 // @Failure      404         {string}  string  "ok"
 // @Failure      500         {string}  string  "ok"
-func (c *Controller) PathParamsExample(w http.ResponseWriter, r *http.Request) (string, int, error) {
-	groupID, err := strconv.Atoi(chi.URLParam(r, "group_id"))
-	if err != nil {
-		return "", http.StatusBadRequest, err
-	}
-	accountID, err := strconv.Atoi(chi.URLParam(r, "account_id"))
-	if err != nil {
-		return "", http.StatusBadRequest, err
-	}
+func (c *Controller) PathParamsExample(req *PathParamsRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+	return fmt.Sprintf("group_id=%d account_id=%d", req.GroupID, req.AccountID), http.StatusOK, nil
+}
 
-	return fmt.Sprintf("group_id=%d account_id=%d", groupID, accountID), http.StatusOK, nil
+// PathParamsRequest example
+type PathParamsRequest struct {
+	GroupID   int `path:"group_id" json:"-" description:"Group ID"`
+	AccountID int `path:"account_id" json:"-" description:"Account ID"`
 }
 
 // HeaderExample godoc
@@ -36,7 +36,7 @@ var (
 
 // AddAccount example
 type AddAccount struct {
-	Name string `json:"name" example:"account name"`
+	Name string `json:"name" example:"account name" validate:"required"`
 }
 
 // Validation example
@@ -1,6 +1,6 @@
 module github.com/go-chai/chai
 
-go 1.18
+go 1.22
 
 require (
 	github.com/getkin/kin-openapi v0.88.0
@@ -15,6 +15,8 @@ require (
 	github.com/stretchr/testify v1.7.0
 	github.com/swaggo/http-swagger v1.2.6
 	github.com/swaggo/swag v1.7.9
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	golang.org/x/net v0.7.0
 )
 
 require (
@@ -29,7 +31,7 @@ require (
 	github.com/mailru/easyjson v0.7.6 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/swaggo/files v0.0.0-20210815190702-a29dd2bc99b2 // indirect
-	golang.org/x/net v0.7.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
 	golang.org/x/text v0.7.0 // indirect
 	golang.org/x/tools v0.1.12 // indirect
@@ -0,0 +1,16 @@
+package chai
+
+import (
+	"github.com/go-chai/chai/asyncapi"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/gorilla/mux"
+)
+
+// AsyncAPI documents the Server-Sent Events and WebSocket routes of r.
+func AsyncAPI(r *mux.Router, opts ...openapi2.Option) (*asyncapi.Document, error) {
+	routes, err := getGorillaRoutes(r)
+	if err != nil {
+		return nil, err
+	}
+	return asyncapi.Docs(routes, opts...)
+}
@@ -7,29 +7,200 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func Get[Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ResHandlerFunc[Res, Err]) {
-	r.Methods(http.MethodGet).Path(path).Handler(chai.NewResHandler(fn))
+// Methodser is implemented by *mux.Router and *Router.
+type Methodser interface {
+	Methods(methods ...string) *mux.Route
 }
 
-func Connect[Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ResHandlerFunc[Res, Err]) {
-	r.Methods(http.MethodConnect).Path(path).Handler(chai.NewResHandler(fn))
+// Router is a *mux.Router whose chai handlers inherit the options of a Config.
+type Router struct {
+	*mux.Router
+	config *chai.Config
 }
 
-func Options[Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ResHandlerFunc[Res, Err]) {
-	r.Methods(http.MethodOptions).Path(path).Handler(chai.NewResHandler(fn))
+// NewRouter wraps r so that the handlers registered through the returned Router use the options of config.
+func NewRouter(r *mux.Router, config *chai.Config) *Router {
+	return &Router{Router: r, config: config}
 }
 
-func Post[Req any, Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Methods(http.MethodPost).Path(path).Handler(chai.NewReqResHandler(fn))
+func (r *Router) Config() *chai.Config {
+	return r.config
 }
-func Put[Req any, Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Methods(http.MethodPut).Path(path).Handler(chai.NewReqResHandler(fn))
+
+// Route is a *mux.Route whose sub-router inherits the Config of the Router it was created on.
+// Its matchers return it, so that Subrouter can be called at the end of a chain, e.g. r.Host(host).PathPrefix("/api").Subrouter().
+type Route struct {
+	*mux.Route
+	config *chai.Config
+}
+
+// Subrouter creates a sub-router for the route like mux's Subrouter. The handlers registered on it inherit the Config.
+func (r *Route) Subrouter() *Router {
+	return &Router{Router: r.Route.Subrouter(), config: r.config}
+}
+
+func (r *Route) route(route *mux.Route) *Route {
+	return &Route{Route: route, config: r.config}
+}
+
+func (r *Route) Name(name string) *Route {
+	return r.route(r.Route.Name(name))
+}
+
+func (r *Route) Headers(pairs ...string) *Route {
+	return r.route(r.Route.Headers(pairs...))
+}
+
+func (r *Route) HeadersRegexp(pairs ...string) *Route {
+	return r.route(r.Route.HeadersRegexp(pairs...))
+}
+
+func (r *Route) Host(tpl string) *Route {
+	return r.route(r.Route.Host(tpl))
+}
+
+func (r *Route) MatcherFunc(f mux.MatcherFunc) *Route {
+	return r.route(r.Route.MatcherFunc(f))
+}
+
+func (r *Route) Methods(methods ...string) *Route {
+	return r.route(r.Route.Methods(methods...))
+}
+
+func (r *Route) Path(tpl string) *Route {
+	return r.route(r.Route.Path(tpl))
+}
+
+func (r *Route) PathPrefix(tpl string) *Route {
+	return r.route(r.Route.PathPrefix(tpl))
+}
+
+func (r *Route) Queries(pairs ...string) *Route {
+	return r.route(r.Route.Queries(pairs...))
+}
+
+func (r *Route) Schemes(schemes ...string) *Route {
+	return r.route(r.Route.Schemes(schemes...))
+}
+
+func (r *Router) route(route *mux.Route) *Route {
+	return &Route{Route: route, config: r.config}
+}
+
+// NewRoute registers an empty route like mux's NewRoute. Its sub-router inherits r's Config, and so do those of the routes
+// returned by the other matchers of r, except Methods, which returns a *mux.Route to implement Methodser.
+func (r *Router) NewRoute() *Route {
+	return r.route(r.Router.NewRoute())
+}
+
+func (r *Router) Name(name string) *Route {
+	return r.route(r.Router.Name(name))
+}
+
+func (r *Router) Headers(pairs ...string) *Route {
+	return r.route(r.Router.Headers(pairs...))
+}
+
+func (r *Router) Host(tpl string) *Route {
+	return r.route(r.Router.Host(tpl))
+}
+
+func (r *Router) MatcherFunc(f mux.MatcherFunc) *Route {
+	return r.route(r.Router.MatcherFunc(f))
+}
+
+func (r *Router) Path(tpl string) *Route {
+	return r.route(r.Router.Path(tpl))
+}
+
+func (r *Router) PathPrefix(tpl string) *Route {
+	return r.route(r.Router.PathPrefix(tpl))
+}
+
+func (r *Router) Queries(pairs ...string) *Route {
+	return r.route(r.Router.Queries(pairs...))
+}
+
+func (r *Router) Schemes(schemes ...string) *Route {
+	return r.route(r.Router.Schemes(schemes...))
+}
+
+// handlerOptions returns the options of a handler registered on r: gorilla's path params, then r's Config, then opts and extra.
+func handlerOptions(r Methodser, opts []chai.Option, extra ...chai.Option) []chai.Option {
+	res := []chai.Option{chai.WithPathParamFunc(pathParam), chai.WithConfig(chai.ConfigOf(r))}
+	res = append(res, opts...)
+
+	return append(res, extra...)
+}
+
+func Get[Res any, Err chai.ErrType](r Methodser, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodGet).Path(path).Handler(chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Head[Res any, Err chai.ErrType](r Methodser, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodHead).Path(path).Handler(chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Connect[Res any, Err chai.ErrType](r Methodser, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodConnect).Path(path).Handler(chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Options[Res any, Err chai.ErrType](r Methodser, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodOptions).Path(path).Handler(chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Post[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodPost).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Put[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodPut).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Patch[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodPatch).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Delete[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodDelete).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+// GetReq registers a GET handler whose request type is filled only from the path, query, headers and cookies.
+func GetReq[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodGet).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// HeadReq registers a HEAD handler whose request type is filled only from the path, query, headers and cookies.
+func HeadReq[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodHead).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// DeleteReq registers a DELETE handler whose request type is filled only from the path, query, headers and cookies.
+func DeleteReq[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodDelete).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// SSE registers a GET handler that streams Server-Sent Events. Its request type is filled only from the path, query, headers and cookies.
+func SSE[Req any, T any, Err chai.ErrType](r Methodser, path string, fn chai.SSEHandlerFunc[Req, T, Err], opts ...chai.Option) {
+	r.Methods(http.MethodGet).Path(path).Handler(chai.NewSSEHandler(fn, handlerOptions(r, opts)...))
+}
+
+// Stream registers a GET handler that streams its items as application/x-ndjson or a JSON array. Its request type is filled only from the path, query, headers and cookies.
+func Stream[Req any, T any, Err chai.ErrType](r Methodser, path string, fn chai.StreamHandlerFunc[Req, T, Err], opts ...chai.Option) {
+	r.Methods(http.MethodGet).Path(path).Handler(chai.NewStreamHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// WebSocket registers a GET handler that upgrades the requests to WebSocket connections on which messages of type In are received
+// and messages of type Out are sent as JSON. Its request type is filled only from the path, query, headers and cookies.
+func WebSocket[Req any, In any, Out any, Err chai.ErrType](r Methodser, path string, fn chai.WebSocketHandlerFunc[Req, In, Out, Err], opts ...chai.Option) {
+	r.Methods(http.MethodGet).Path(path).Handler(chai.NewWebSocketHandler(fn, handlerOptions(r, opts)...))
 }
 
-func Patch[Req any, Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Methods(http.MethodPatch).Path(path).Handler(chai.NewReqResHandler(fn))
+// Upload registers a POST handler whose request type is decoded from a multipart/form-data body, see chai.MultipartFields.
+func Upload[Req any, Res any, Err chai.ErrType](r Methodser, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Methods(http.MethodPost).Path(path).Handler(chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithMultipart())...))
 }
 
-func Delete[Req any, Res any, Err chai.ErrType](r *mux.Router, path string, fn chai.ReqResHandlerFunc[Req, Res, Err]) {
-	r.Methods(http.MethodDelete).Path(path).Handler(chai.NewReqResHandler(fn))
+func pathParam(r *http.Request, name string) string {
+	return mux.Vars(r)[name]
 }
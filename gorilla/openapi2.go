@@ -1,45 +1,104 @@
 package chai
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
-	chai "github.com/go-chai/chai/chi"
+	"github.com/go-chai/chai/chai"
 	"github.com/go-chai/chai/openapi2"
 	"github.com/go-openapi/spec"
 	"github.com/gorilla/mux"
 )
 
-func OpenAPI2(r *mux.Router) (*spec.Swagger, error) {
+// MethodlessRouteMethods are the methods the chai handlers of the routes without a method matcher are documented for,
+// since they match every method. Those routes are not documented if it is empty, nor when their handlers are not chai handlers,
+// e.g. file servers mounted with PathPrefix.
+var MethodlessRouteMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+func OpenAPI2(r *mux.Router, opts ...openapi2.Option) (*spec.Swagger, error) {
 	routes, err := getGorillaRoutes(r)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return openapi2.Docs(routes)
+	return openapi2.Docs(routes, opts...)
 }
 
+// getGorillaRoutes returns the routes of r that have a handler. Their query and header matchers are documented as required parameters,
+// their host and scheme matchers restrict their operations to hosts and schemes, and their names are the operationIds of their operations,
+// suffixed with the method if the route matches several.
 func getGorillaRoutes(r *mux.Router) ([]*openapi2.Route, error) {
 	routes := make([]*openapi2.Route, 0)
 
 	err := r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+
 		path, err := route.GetPathTemplate()
 		if err != nil {
 			return err
 		}
+
 		methods, err := route.GetMethods()
 		if err != nil && !strings.Contains(err.Error(), "route doesn't have methods") {
 			return err
 		}
+		if len(methods) == 0 {
+			if _, ok := route.GetHandler().(chai.Handlerer); !ok {
+				return nil
+			}
+
+			methods = MethodlessRouteMethods
+		}
+
+		host, err := route.GetHostTemplate()
+		if err != nil && !strings.Contains(err.Error(), "route doesn't have a host") {
+			return err
+		}
+
+		queries, err := queryParams(route)
+		if err != nil {
+			return err
+		}
+
+		headers, schemes, err := routeMatchers(route)
+		if err != nil {
+			return err
+		}
 
 		for _, method := range methods {
-			params, regexlessPath := chai.ParsePathParams(path)
+			params, regexlessPath := openapi2.ParsePathParams(path)
+			params = append(params, queries...)
+			params = append(params, headers...)
+
+			name := route.GetName()
+			if name != "" && len(methods) > 1 {
+				name += "-" + strings.ToLower(method)
+			}
 
 			routes = append(routes, &openapi2.Route{
 				Method:  method,
 				Path:    regexlessPath,
 				Params:  params,
 				Handler: route.GetHandler(),
+				Name:    name,
+				Host:    host,
+				Schemes: schemes,
 			})
 		}
 
@@ -52,3 +111,105 @@ func getGorillaRoutes(r *mux.Router) ([]*openapi2.Route, error) {
 
 	return routes, nil
 }
+
+// queryParams returns the required query parameters of the query matchers of the route. The values of the variables of the templates
+// must match their patterns, and the other values are the only ones allowed.
+func queryParams(route *mux.Route) ([]spec.Parameter, error) {
+	templates, err := route.GetQueriesTemplates()
+	if err != nil {
+		if strings.Contains(err.Error(), "route doesn't have queries") {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	res := make([]spec.Parameter, 0, len(templates))
+
+	for _, tpl := range templates {
+		key, value, _ := strings.Cut(tpl, "=")
+
+		p := spec.QueryParam(key).Typed("string", "")
+		p.Required = true
+
+		vars, regexless := openapi2.ParsePathParams(value)
+		switch {
+		case len(vars) == 1 && regexless == "{"+vars[0].Name+"}":
+			p.SimpleSchema = vars[0].SimpleSchema
+			p.Pattern = vars[0].Pattern
+		case len(vars) == 0 && value != "":
+			p.WithEnum(value)
+		}
+
+		res = append(res, *p)
+	}
+
+	return res, nil
+}
+
+// routeMatchers returns the required header parameters of the header matchers of the route, and the schemes of its scheme matchers.
+// mux has no getters for them, unlike the host, query, method and path matchers, so they are read from the unexported matchers
+// of the route, as of mux v1.8.0, and an error is returned if the route no longer has them. TestRouteMatchers checks that they are
+// still read.
+func routeMatchers(route *mux.Route) ([]spec.Parameter, []string, error) {
+	matchers := reflect.ValueOf(route).Elem().FieldByName("matchers")
+	if !matchers.IsValid() || matchers.Kind() != reflect.Slice {
+		return nil, nil, errors.New("the matchers of the mux routes cannot be read")
+	}
+
+	headers := make([]spec.Parameter, 0)
+	var schemes []string
+
+	for i := 0; i < matchers.Len(); i++ {
+		m := matchers.Index(i)
+		if m.Kind() == reflect.Interface {
+			m = m.Elem()
+		}
+
+		switch m.Type().Name() {
+		case "headerMatcher":
+			iter := m.MapRange()
+			for iter.Next() {
+				p := headerParam(iter.Key().String())
+				if v := iter.Value().String(); v != "" {
+					p.WithEnum(v)
+				}
+				headers = append(headers, *p)
+			}
+		case "headerRegexMatcher":
+			iter := m.MapRange()
+			for iter.Next() {
+				re := iter.Value()
+				if re.Type() != regexpType {
+					return nil, nil, fmt.Errorf("the header regexps of the mux routes are %s, not *regexp.Regexp", re.Type())
+				}
+
+				p := headerParam(iter.Key().String())
+				if !re.IsNil() {
+					// The value comes from an unexported field, so it cannot be turned back into an interface.
+					p.Pattern = (*regexp.Regexp)(re.UnsafePointer()).String()
+				}
+				headers = append(headers, *p)
+			}
+		case "schemeMatcher":
+			for j := 0; j < m.Len(); j++ {
+				schemes = append(schemes, m.Index(j).String())
+			}
+		}
+	}
+
+	sort.Slice(headers, func(i, j int) bool {
+		return headers[i].Name < headers[j].Name
+	})
+
+	return headers, schemes, nil
+}
+
+var regexpType = reflect.TypeOf((*regexp.Regexp)(nil))
+
+func headerParam(name string) *spec.Parameter {
+	p := spec.HeaderParam(http.CanonicalHeaderKey(name)).Typed("string", "")
+	p.Required = true
+
+	return p
+}
@@ -0,0 +1,108 @@
+package chai_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chai/chai/chai"
+	chaigorilla "github.com/go-chai/chai/gorilla"
+	"github.com/go-chai/chai/internal/tests"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI2(t *testing.T) {
+	defer func(methods []string) { chaigorilla.MethodlessRouteMethods = methods }(chaigorilla.MethodlessRouteMethods)
+
+	chaigorilla.MethodlessRouteMethods = []string{http.MethodGet, http.MethodPost}
+
+	type args struct {
+		r *mux.Router
+	}
+	tcs := []struct {
+		name     string
+		args     args
+		filePath string
+	}{
+		{
+			name: "matchers",
+			args: args{
+				r: func() *mux.Router {
+					r := mux.NewRouter()
+					s := r.Host("{tenant:[a-z]+}.example.com").Schemes("https").Subrouter()
+
+					s.Methods(http.MethodGet).
+						Path("/accounts/{id:[0-9]+}").
+						Queries("limit", "{limit:[0-9]+}", "sort", "asc").
+						Headers("X-Version", "1").
+						HeadersRegexp("X-Trace-Id", "^[a-f0-9]+$").
+						Name("show-account").
+						Handler(chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}))
+
+					s.Path("/echo").
+						Name("echo").
+						Handler(chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestInnerResponse, int, error) {
+							return nil, 0, nil
+						}))
+
+					s.PathPrefix("/assets/").Handler(http.FileServer(http.Dir("testdata")))
+
+					return r
+				}(),
+			},
+			filePath: "testdata/matchers.json",
+		},
+		{
+			name: "host",
+			args: args{
+				r: func() *mux.Router {
+					r := mux.NewRouter()
+					s := r.Host("api.example.com").Subrouter()
+
+					chaigorilla.Get(s, "/ping", func(w http.ResponseWriter, r *http.Request) (*string, int, error) {
+						return nil, 0, nil
+					})
+
+					return r
+				}(),
+			},
+			filePath: "testdata/host.json",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chaigorilla.OpenAPI2(tt.args.r, openapi2.WithReflection())
+			require.NoError(t, err)
+			require.JSONEq(t, tests.LoadFile(t, tt.filePath), tests.JS(got))
+		})
+	}
+}
+
+// TestRouteMatchers checks that the header and scheme matchers, which mux keeps unexported, are still documented.
+func TestRouteMatchers(t *testing.T) {
+	r := mux.NewRouter()
+	r.Methods(http.MethodGet).
+		Path("/ping").
+		Schemes("https", "http").
+		Headers("X-Version", "1").
+		HeadersRegexp("X-Trace-Id", "^[a-f0-9]+$").
+		Handler(chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*string, int, error) {
+			return nil, 0, nil
+		}))
+
+	docs, err := chaigorilla.OpenAPI2(r)
+	require.NoError(t, err)
+
+	op := docs.Paths.Paths["/ping"].Get
+	require.NotNil(t, op)
+	require.Equal(t, []string{"https", "http"}, op.Schemes)
+
+	require.Len(t, op.Parameters, 2)
+	require.Equal(t, "X-Trace-Id", op.Parameters[0].Name)
+	require.Equal(t, "^[a-f0-9]+$", op.Parameters[0].Pattern)
+	require.Equal(t, "X-Version", op.Parameters[1].Name)
+	require.Equal(t, []interface{}{"1"}, op.Parameters[1].Enum)
+}
@@ -0,0 +1,47 @@
+package chai_test
+
+import (
+	"net/http"
+	"testing"
+
+	chaigorilla "github.com/go-chai/chai/gorilla"
+	"github.com/go-chai/chai/internal/tests"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI31(t *testing.T) {
+	type args struct {
+		r *mux.Router
+	}
+	tcs := []struct {
+		name     string
+		args     args
+		filePath string
+	}{
+		{
+			name: "t1",
+			args: args{
+				r: func() *mux.Router {
+					r := mux.NewRouter()
+
+					// @Summary  Show a nullable response
+					// @Tags     nullable
+					chaigorilla.GetReq(r, "/nullable/{id}", func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestNullableResponse, int, error) {
+						return nil, 0, nil
+					})
+
+					return r
+				}(),
+			},
+			filePath: "testdata/openapi31_t1.json",
+		},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chaigorilla.OpenAPI31(tt.args.r)
+			require.NoError(t, err)
+			require.JSONEq(t, tests.LoadFile(t, tt.filePath), tests.JS(got))
+		})
+	}
+}
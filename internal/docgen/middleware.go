@@ -0,0 +1,102 @@
+package docgen
+
+import (
+	"net/http"
+
+	"github.com/go-chai/chai/chai"
+)
+
+// MiddlewareDocs returns the contributions of the documented middlewares of a route, see chai.MiddlewareDocsOf, merged in order.
+// The headers and responses of the first middlewares take precedence, and the security requirements and tags are not repeated.
+func MiddlewareDocs(middlewares []func(http.Handler) http.Handler) chai.MiddlewareDocs {
+	var res chai.MiddlewareDocs
+
+	for _, mw := range middlewares {
+		docs, ok := chai.MiddlewareDocsOf(mw)
+		if !ok {
+			continue
+		}
+
+		for _, h := range docs.Headers {
+			if !containsHeader(res.Headers, h.Name) {
+				res.Headers = append(res.Headers, h)
+			}
+		}
+
+		for _, sr := range docs.Security {
+			if !ContainsSecurity(res.Security, sr) {
+				res.Security = append(res.Security, sr)
+			}
+		}
+
+		for _, tag := range docs.Tags {
+			if !contains(res.Tags, tag) {
+				res.Tags = append(res.Tags, tag)
+			}
+		}
+
+		for code, description := range docs.Responses {
+			if res.Responses == nil {
+				res.Responses = map[int]string{}
+			}
+
+			if _, ok := res.Responses[code]; !ok {
+				res.Responses[code] = description
+			}
+		}
+	}
+
+	return res
+}
+
+// ContainsSecurity reports whether security has the requirement sr, with the same schemes and scopes.
+func ContainsSecurity(security []map[string][]string, sr map[string][]string) bool {
+	for _, s := range security {
+		if sameSecurity(s, sr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameSecurity(s, s2 map[string][]string) bool {
+	if len(s) != len(s2) {
+		return false
+	}
+
+	for name, scopes := range s {
+		scopes2, ok := s2[name]
+		if !ok || len(scopes) != len(scopes2) {
+			return false
+		}
+
+		for i := range scopes {
+			if scopes[i] != scopes2[i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func containsHeader(headers []chai.MiddlewareHeader, name string) bool {
+	for _, h := range headers {
+		if h.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
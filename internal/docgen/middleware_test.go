@@ -0,0 +1,38 @@
+package docgen
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareDocs(t *testing.T) {
+	noop := func(next http.Handler) http.Handler {
+		return next
+	}
+
+	auth := chai.DocumentMiddleware(noop, chai.MiddlewareDocs{
+		Headers:   []chai.MiddlewareHeader{{Name: "X-Api-Key", Description: "The API key", Required: true}},
+		Security:  []map[string][]string{{"ApiKeyAuth": {}}},
+		Responses: map[int]string{http.StatusUnauthorized: "Unauthorized"},
+		Tags:      []string{"auth"},
+	})
+
+	otherAuth := chai.DocumentMiddleware(noop, chai.MiddlewareDocs{
+		Headers:   []chai.MiddlewareHeader{{Name: "X-Api-Key", Description: "Another key"}},
+		Security:  []map[string][]string{{"ApiKeyAuth": {}}, {"OAuth2": {"read"}}},
+		Responses: map[int]string{http.StatusUnauthorized: "Denied", http.StatusTooManyRequests: "Too Many Requests"},
+		Tags:      []string{"auth", "limited"},
+	})
+
+	got := MiddlewareDocs([]func(http.Handler) http.Handler{auth, noop, otherAuth})
+
+	require.Equal(t, chai.MiddlewareDocs{
+		Headers:   []chai.MiddlewareHeader{{Name: "X-Api-Key", Description: "The API key", Required: true}},
+		Security:  []map[string][]string{{"ApiKeyAuth": {}}, {"OAuth2": {"read"}}},
+		Responses: map[int]string{http.StatusUnauthorized: "Unauthorized", http.StatusTooManyRequests: "Too Many Requests"},
+		Tags:      []string{"auth", "limited"},
+	}, got)
+}
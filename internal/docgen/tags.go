@@ -0,0 +1,115 @@
+// Package docgen has the parts of the generators of the OpenAPI and AsyncAPI documents that do not depend on the types of the documents.
+package docgen
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-chai/chai/chai"
+)
+
+// TypedValue parses the value of a tag as a value of the schema type, and returns it as is if it does not parse.
+func TypedValue(typ, v string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+
+	return v
+}
+
+// Example returns the value of the `example` tag of f, as a value of the schema type typ, or for arrays as a list of values
+// of the type of their items, itemType.
+func Example(f reflect.StructField, typ, itemType string) (any, bool) {
+	example, ok := f.Tag.Lookup("example")
+	if !ok {
+		return nil, false
+	}
+
+	if typ != "array" {
+		return TypedValue(typ, example), true
+	}
+
+	values := make([]any, 0)
+	for _, e := range strings.Split(example, ",") {
+		values = append(values, TypedValue(itemType, e))
+	}
+
+	return values, true
+}
+
+// Default returns the value of the `default` tag of f as a value of the schema type typ.
+func Default(f reflect.StructField, typ string) (any, bool) {
+	def, ok := f.Tag.Lookup("default")
+	if !ok {
+		return nil, false
+	}
+
+	return TypedValue(typ, def), true
+}
+
+// Extension is a vendor extension set with the `extensions` tag of a field.
+type Extension struct {
+	Name  string
+	Value any
+}
+
+// Extensions returns the vendor extensions set with the `extensions` tag of f, in order:
+// "x-a=b" sets x-a to "b", "x-a" sets it to true and "!x-a" to false.
+func Extensions(f reflect.StructField) []Extension {
+	extensions := f.Tag.Get("extensions")
+	if extensions == "" {
+		return nil
+	}
+
+	res := make([]Extension, 0)
+	for _, ext := range strings.Split(extensions, ",") {
+		name, value, hasValue := strings.Cut(ext, "=")
+		switch {
+		case hasValue:
+			res = append(res, Extension{Name: name, Value: value})
+		case strings.HasPrefix(name, "!"):
+			res = append(res, Extension{Name: name[1:], Value: false})
+		default:
+			res = append(res, Extension{Name: name, Value: true})
+		}
+	}
+
+	return res
+}
+
+// Enum returns the values of the enum rule as values of the schema type typ.
+func Enum(rules *chai.Rules, typ string) []any {
+	if len(rules.Enum) == 0 {
+		return nil
+	}
+
+	res := make([]any, 0, len(rules.Enum))
+	for _, e := range rules.Enum {
+		res = append(res, TypedValue(typ, e))
+	}
+
+	return res
+}
+
+// IsParam reports whether f is bound from the path, query, headers or cookies instead of the body.
+func IsParam(f reflect.StructField) bool {
+	for _, in := range []string{chai.InPath, chai.InQuery, chai.InHeader, chai.InCookie} {
+		if _, ok := f.Tag.Lookup(in); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+)
+
+// Writer writes documents as JSON and YAML files.
+type Writer struct {
+	jsonIndent func(data interface{}) ([]byte, error)
+	jsonToYAML func(data []byte) ([]byte, error)
+}
+
+func NewWriter() *Writer {
+	return &Writer{
+		jsonIndent: func(data interface{}) ([]byte, error) {
+			return json.MarshalIndent(data, "", "    ")
+		},
+		jsonToYAML: yaml.JSONToYAML,
+	}
+}
+
+// Write writes doc to <name>.json and <name>.yaml in dir.
+func (w *Writer) Write(doc any, dir, name string) error {
+	b, err := w.jsonIndent(doc)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	jsonFileName := filepath.Join(dir, name+".json")
+	yamlFileName := filepath.Join(dir, name+".yaml")
+
+	err = os.WriteFile(jsonFileName, b, 0o644)
+	if err != nil {
+		return err
+	}
+
+	y, err := w.jsonToYAML(b)
+	if err != nil {
+		return fmt.Errorf("cannot convert json to yaml error: %s", err)
+	}
+
+	err = os.WriteFile(yamlFileName, y, 0o644)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("create %s.json at %+v", name, jsonFileName)
+	log.Printf("create %s.yaml at %+v", name, yamlFileName)
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "docs")
+
+	err := NewWriter().Write(map[string]string{"asyncapi": "2.6.0"}, dir, "asyncapi")
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(dir, "asyncapi.json"))
+	require.NoError(t, err)
+	require.Equal(t, "{\n    \"asyncapi\": \"2.6.0\"\n}", string(b))
+
+	b, err = os.ReadFile(filepath.Join(dir, "asyncapi.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "asyncapi: 2.6.0\n", string(b))
+}
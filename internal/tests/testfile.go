@@ -133,4 +133,10 @@ func Simple4() func() (int) {
 
 	// Simple4 inner comment
 	return func() (int) { return 1}
-} 
\ No newline at end of file
+} 
+type Methods struct{}
+
+// Method correct comment
+func (m *Methods) Method() (int, int) {
+	return 1, 2
+}
@@ -1,5 +1,12 @@
 package tests
 
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/go-chai/chai/chai"
+)
+
 type TestStruct struct {
 	Foo string `json:"foo"`
 	Bar int    `json:"bar"`
@@ -50,3 +57,128 @@ type TestErrorMapPtr map[string]string
 func (e *TestErrorMapPtr) Error() string {
 	return "test error map ptr"
 }
+
+type TestParamsRequest struct {
+	ID      int      `path:"id" json:"-"`
+	Q       string   `query:"q" json:"-"`
+	Tags    []string `query:"tags" json:"-"`
+	Auth    string   `header:"Authorization" json:"-"`
+	Session string   `cookie:"session" json:"-"`
+
+	Foo string `json:"foo"`
+}
+
+type TestParamsOnlyRequest struct {
+	ID    int64   `path:"id"`
+	Limit *int    `query:"limit" description:"max number of items"`
+	Trace string  `header:"X-Trace-Id"`
+	Score float64 `query:"score"`
+}
+
+type TestValidatedRequest struct {
+	Limit int `query:"limit" json:"-" validate:"min=1,max=100"`
+
+	Name   string              `json:"name" validate:"required,max=10"`
+	Email  string              `json:"email" format:"email"`
+	Code   string              `json:"code" pattern:"^[A-Z]{3}$" validate:"len=3"`
+	Kind   string              `json:"kind" validate:"oneof=a b"`
+	Age    int                 `json:"age" minimum:"18"`
+	Items  []TestValidatedItem `json:"items" validate:"max=2"`
+	Parent *TestValidatedItem  `json:"parent"`
+}
+
+type TestValidatedItem struct {
+	ID int `json:"id" validate:"required"`
+}
+
+type TestEventsRequest struct {
+	Topic       string `path:"topic"`
+	LastEventID string `header:"Last-Event-ID"`
+}
+
+type TestEvent struct {
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+type TestUploadRequest struct {
+	ID int `path:"id" json:"-"`
+
+	Caption     string                  `form:"caption" validate:"max=20"`
+	Tags        []string                `form:"tags"`
+	Image       *multipart.FileHeader   `form:"image" maxSize:"8" validate:"required" description:"The image"`
+	Attachments []*multipart.FileHeader `form:"attachments"`
+}
+
+type TestStreamingUploadRequest struct {
+	Caption string      `form:"caption"`
+	Files   *chai.Parts `form:"files"`
+}
+
+type TestLimitedStreamingUploadRequest struct {
+	Caption string      `form:"caption"`
+	Files   *chai.Parts `form:"files" maxSize:"2"`
+}
+
+type TestNullableResponse struct {
+	ID      int     `json:"id" example:"1"`
+	Name    *string `json:"name" extensions:"x-nullable" example:"foo"`
+	Version string  `json:"version" enums:"v1"`
+}
+
+// TestCat is a cat.
+type TestCat struct {
+	// The name of the cat
+	Name  string `json:"name" validate:"required"`
+	Lives int    `json:"lives"`
+}
+
+type TestDog struct {
+	Name  string `json:"name" validate:"required"`
+	Breed string `json:"breed"`
+}
+
+// TestPet is either a cat or a dog.
+type TestPet struct {
+	Cat *TestCat
+	Dog *TestDog
+}
+
+func (TestPet) OneOf() []any {
+	return []any{(*TestCat)(nil), (*TestDog)(nil)}
+}
+
+// TestSearchResult matches a cat, an event or both.
+type TestSearchResult map[string]interface{}
+
+func (TestSearchResult) AnyOf() []any {
+	return []any{(*TestCat)(nil), (*TestEvent)(nil)}
+}
+
+type TestAdoptRequest struct {
+	ID int `path:"id" json:"-"`
+
+	Pet   TestPet            `json:"pet"`
+	Owner TestInnerResponse  `json:"owner"`
+	Notes []TestSearchResult `json:"notes"`
+}
+
+type TestReflectedBase struct {
+	ID        int       `json:"id" validate:"required"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TestReflected struct {
+	TestReflectedBase
+
+	Name     string            `json:"name" description:"The name" example:"foo" validate:"max=10"`
+	Tags     []string          `json:"tags,omitempty" enums:"a,b"`
+	Labels   map[string]int    `json:"labels"`
+	Timeout  time.Duration     `json:"timeout"`
+	Data     []byte            `json:"data"`
+	Count    int64             `json:"count,string"`
+	Parent   *TestReflected    `json:"parent,omitempty"`
+	Inner    TestInnerResponse `json:"inner"`
+	Internal string            `json:"-"`
+	Q        string            `query:"q"`
+}
@@ -2,6 +2,7 @@ package openapi2
 
 import (
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
 	"os"
@@ -74,9 +75,77 @@ func getCallerFrame(fn any) *runtime.Frame {
 	if frame.Entry == 0 {
 		return nil
 	}
+	if strings.HasSuffix(frame.Function, "-fm") {
+		frame.File, frame.Line = findMethod(frame.Function)
+	}
 	return &frame
 }
 
+// findMethod returns the file and the line of the declaration of the method whose method value is the wrapper function name,
+// e.g. "example.com/pkg.(*Controller).ShowAccount-fm", since the runtime reports the wrappers as <autogenerated> code.
+func findMethod(name string) (string, int) {
+	name = strings.TrimSuffix(name, "-fm")
+
+	slash := strings.LastIndex(name, "/") + 1
+	dot := strings.Index(name[slash:], ".")
+	if dot < 0 {
+		return "", 0
+	}
+
+	pkgPath, sel := name[:slash+dot], name[slash+dot+1:]
+
+	dot = strings.LastIndex(sel, ".")
+	if dot < 0 {
+		return "", 0
+	}
+
+	recv, method := strings.Trim(sel[:dot], "(*)"), sel[dot+1:]
+	if i := strings.Index(recv, "["); i >= 0 {
+		recv = recv[:i]
+	}
+
+	wd, _ := os.Getwd()
+	pkg, err := build.Import(pkgPath, wd, 0)
+	if err != nil {
+		return "", 0
+	}
+
+	for _, name := range pkg.GoFiles {
+		file := filepath.Join(pkg.Dir, name)
+		fset := token.NewFileSet()
+
+		astFile, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range astFile.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if ok && fd.Recv != nil && fd.Name.Name == method && recvName(fd.Recv.List[0].Type) == recv {
+				return file, pos(fset, fd)
+			}
+		}
+	}
+
+	return "", 0
+}
+
+// recvName returns the name of the type of a method receiver, without its pointer and type parameters.
+func recvName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return recvName(t.X)
+	case *ast.IndexExpr:
+		return recvName(t.X)
+	case *ast.IndexListExpr:
+		return recvName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+
+	return ""
+}
+
 func getPkgName(file string, src any) string {
 	fset := token.NewFileSet()
 	astFile, err := parser.ParseFile(fset, file, src, parser.PackageClauseOnly)
@@ -130,6 +130,13 @@ func TestGetFuncInfo(t *testing.T) {
 			},
 			want: want{comment: "Simple4 outer comment\n"},
 		},
+		{
+			name: "method value",
+			args: args{
+				fn: (&tests.Methods{}).Method,
+			},
+			want: want{comment: "Method correct comment\n"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,48 @@
+package openapi2
+
+import (
+	"net/http"
+
+	"github.com/go-chai/chai/internal/docgen"
+	"github.com/go-chai/swag"
+	"github.com/go-openapi/spec"
+)
+
+// addMiddlewareDocs merges the contributions of the documented middlewares of a route into its operation.
+// The parameters, responses, security requirements and tags that the operation already has take precedence.
+func addMiddlewareDocs(op *swag.Operation, middlewares []func(http.Handler) http.Handler) {
+	docs := docgen.MiddlewareDocs(middlewares)
+
+	headers := make([]spec.Parameter, 0, len(docs.Headers))
+	for _, h := range docs.Headers {
+		p := spec.HeaderParam(h.Name).Typed("string", "").WithDescription(h.Description)
+		p.Required = h.Required
+		headers = append(headers, *p)
+	}
+
+	if len(headers) > 0 {
+		op.Parameters = mergeParameters(headers, op.Parameters)
+	}
+
+	for _, sr := range docs.Security {
+		if !docgen.ContainsSecurity(op.Security, sr) {
+			op.Security = append(op.Security, sr)
+		}
+	}
+
+	for _, tag := range docs.Tags {
+		if !contains(op.Tags, tag) {
+			op.Tags = append(op.Tags, tag)
+		}
+	}
+
+	for code, description := range docs.Responses {
+		if op.Responses != nil {
+			if _, ok := op.Responses.StatusCodeResponses[code]; ok {
+				continue
+			}
+		}
+
+		op.RespondsWith(code, spec.NewResponse().WithDescription(description))
+	}
+}
@@ -1,6 +1,8 @@
 package openapi2
 
 import (
+	goparser "go/parser"
+	"go/token"
 	"log"
 	"net/http"
 	"os"
@@ -28,26 +30,178 @@ type Route struct {
 	Params      []spec.Parameter
 	Handler     http.Handler
 	Middlewares []func(http.Handler) http.Handler
+	// Name is the operationId of the route's operation, unless its annotations set one.
+	Name string
+	// Host and Schemes restrict the route to a host, e.g. "{subdomain:[a-z]+}.example.com", and to URL schemes, e.g. "https".
+	Host    string
+	Schemes []string
+}
+
+// Option configures how the routes are documented.
+type Option func(*options)
+
+type options struct {
+	reflect bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
 }
 
-func Docs(routes []*Route) (*spec.Swagger, error) {
+// WithReflection derives the schemas from the types of the handlers by reflection, see Reflector, instead of parsing
+// the Go source of the handlers, so that a binary deployed without its source can document its own routes.
+// The swaggo annotations of the handlers are not parsed then.
+func WithReflection() Option {
+	return func(o *options) {
+		o.reflect = true
+	}
+}
+
+func Docs(routes []*Route, opts ...Option) (*spec.Swagger, error) {
 	var err error
 
-	parser := swag.New(swag.SetDebugger(log.Default()), func(p *swag.Parser) {
+	parser := NewParser()
+
+	for _, route := range routes {
+		if Undocumented(route) {
+			continue
+		}
+
+		err = RegisterRoute(parser, route, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	swagger := parser.GetSwagger()
+	if swagger.Host == "" {
+		swagger.Host = commonHost(routes)
+	}
+
+	return swagger, nil
+}
+
+// commonHost returns the host of the routes if they all have the same one, since Swagger 2 documents have a single host.
+// Swagger 2 hosts cannot be templated, so it is empty if the host has variables, e.g. "{tenant:[a-z]+}.example.com".
+func commonHost(routes []*Route) string {
+	if len(routes) == 0 {
+		return ""
+	}
+
+	for _, route := range routes {
+		if route.Host != routes[0].Host {
+			return ""
+		}
+	}
+
+	vars, host := ParsePathParams(routes[0].Host)
+	if len(vars) > 0 {
+		return ""
+	}
+
+	return host
+}
+
+// Undocumented reports whether the handler of the route is excluded from the documents, see chai.WithoutDocs.
+func Undocumented(route *Route) bool {
+	dh, ok := route.Handler.(chai.DocHandler)
+
+	return ok && dh.Undocumented()
+}
+
+// NewParser returns a swaggo parser configured like the one Docs documents the routes with.
+func NewParser() *swag.Parser {
+	return swag.New(swag.SetDebugger(log.Default()), func(p *swag.Parser) {
 		p.ParseDependency = true
 	})
+}
 
-	for _, route := range routes {
-		err = RegisterRoute(parser, route)
+func RegisterRoute(parser *swag.Parser, route *Route, opts ...Option) error {
+	hd, err := ParseRoute(parser, route, opts...)
+	if err != nil {
+		return err
+	}
+
+	addOperation(parser.GetSwagger(), route.Path, route.Method, hd.Operation)
+
+	return nil
+}
+
+// ParseRoute documents the operation of the route like RegisterRoute, with its responses and the contributions of its documented
+// middlewares, see chai.DocumentMiddleware, without adding it to the parser's swagger.
+// The definitions of its types are added to the parser's swagger.
+func ParseRoute(parser *swag.Parser, route *Route, opts ...Option) (*HandlerDocs, error) {
+	hd, err := ParseHandler(parser, route, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if hd.ID == "" {
+		hd.ID = route.Name
+	}
+
+	if len(hd.Schemes) == 0 {
+		hd.Schemes = route.Schemes
+	}
+
+	h := route.Handler
+
+	if reqer, ok := h.(chai.Reqer); ok {
+		err = addValidationRules(parser.GetSwagger().Definitions, reflect.TypeOf(reqer.Req()))
 		if err != nil {
 			return nil, err
 		}
+
+		if DecodesStrictly(h) {
+			err = strictBody(parser.GetSwagger().Definitions, &hd.Operation.Operation, reflect.TypeOf(reqer.Req()))
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return parser.GetSwagger(), nil
+	err = updateResponses(hd, h, parser.GetSwagger())
+	if err != nil {
+		return nil, err
+	}
+
+	addMiddlewareDocs(hd.Operation, route.Middlewares)
+
+	return hd, nil
+}
+
+// HandlerDocs is the operation documented by the swaggo annotations of a handler function and by its request type.
+type HandlerDocs struct {
+	*swag.Operation
+
+	schema func(v any) (*spec.Schema, error)
+}
+
+// ParseHandler parses the swaggo annotations of the function of the route's handler, and adds the parameters
+// and the body of its request type to the operation. The definitions of the request types are added to the parser's swagger.
+// WithReflection skips the annotations, and so does chai.Doc for the handlers that are not functions.
+func ParseHandler(parser *swag.Parser, route *Route, opts ...Option) (*HandlerDocs, error) {
+	hd, err := ParseAnnotations(parser, route, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = updateRequests(hd, route.Handler, route.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	return hd, nil
 }
 
-func RegisterRoute(parser *swag.Parser, route *Route) error {
+// ParseAnnotations parses the swaggo annotations of the function of the route's handler, without documenting its types.
+// The operation is empty with WithReflection, and for the handlers wrapped with chai.Doc that are not functions.
+func ParseAnnotations(parser *swag.Parser, route *Route, opts ...Option) (*HandlerDocs, error) {
 	var h = route.Handler
 	var hh any = h
 
@@ -56,30 +210,62 @@ func RegisterRoute(parser *swag.Parser, route *Route) error {
 		hh = ch.Handler()
 	}
 
+	_, doc := h.(chai.DocHandler)
+
+	if newOptions(opts).reflect || doc && reflect.TypeOf(hh).Kind() != reflect.Func {
+		r := NewReflector(parser.GetSwagger().Definitions)
+
+		return &HandlerDocs{Operation: swag.NewOperation(parser), schema: r.Schema}, nil
+	}
+
 	fi := getFuncInfo(hh)
 
 	if fi.Unresolvable {
-		return errors.New("failed to resolve func info")
+		return nil, errors.New("failed to resolve func info")
 	}
 
 	op, err := parseSwaggoAnnotations(fi, parser)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = updateRequests(fi, op, h, route.Params)
+	return &HandlerDocs{
+		Operation: op,
+		schema: func(v any) (*spec.Schema, error) {
+			return op.ParseAPIObjectSchema("object", typeName(v), fi.ASTFile)
+		},
+	}, nil
+}
+
+// Schema returns the schema of the type v points to, resolved from the file of the handler function, or by reflection.
+// The definitions it refers to are added to the parser's swagger.
+func (hd *HandlerDocs) Schema(v any) (*spec.Schema, error) {
+	return hd.schema(v)
+}
+
+// FileSchema returns the schema of the type v points to, resolved from the given Go file, which must refer to the type,
+// or by reflection with WithReflection. The definitions it refers to are added to the parser's swagger.
+func FileSchema(parser *swag.Parser, file string, v any, opts ...Option) (*spec.Schema, error) {
+	if newOptions(opts).reflect {
+		return NewReflector(parser.GetSwagger().Definitions).Schema(v)
+	}
+
+	astFile, err := goparser.ParseFile(token.NewFileSet(), file, nil, goparser.ImportsOnly)
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "failed to parse file")
 	}
 
-	err = updateResponses(fi, op, h)
+	pkg, err := getPkgPath(file)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	addOperation(parser.GetSwagger(), route.Path, route.Method, op)
+	err = parser.GetAllGoFileInfoAndParseTypes(pkg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse docs spec")
+	}
 
-	return nil
+	return swag.NewOperation(parser).ParseAPIObjectSchema("object", typeName(v), astFile)
 }
 
 func parseSwaggoAnnotations(fi funcInfo, parser *swag.Parser) (*swag.Operation, error) {
@@ -120,9 +306,11 @@ func getPkgPath(file string) (string, error) {
 	return filepath.Dir(file), nil
 }
 
-func updateRequests(fi funcInfo, op *swag.Operation, h http.Handler, params []spec.Parameter) error {
+func updateRequests(hd *HandlerDocs, h http.Handler, params []spec.Parameter) error {
 	var err error
 
+	op := hd.Operation
+
 	reqer, ok := h.(chai.Reqer)
 	if !ok {
 		op.Parameters = mergeParameters(params, op.Parameters)
@@ -130,11 +318,36 @@ func updateRequests(fi funcInfo, op *swag.Operation, h http.Handler, params []sp
 		return nil
 	}
 
+	reqType := reflect.TypeOf(reqer.Req())
+
+	reqParams, err := tagParams(reqType)
+	if err != nil {
+		return err
+	}
+	reqParams = inheritPatterns(reqParams, params)
+
+	if !DecodesBody(h) || !chai.HasBody(reqType) {
+		op.Parameters = mergeParameters(params, reqParams, op.Parameters)
+
+		return nil
+	}
+
 	if len(op.Consumes) == 0 {
-		op.Consumes = append(op.Consumes, "application/json")
+		op.Consumes = append(op.Consumes, RequestContentTypes(h)...)
+	}
+
+	if contains(RequestContentTypes(h), chai.MultipartContentType) {
+		formParams, err := formDataParams(reqType)
+		if err != nil {
+			return err
+		}
+
+		op.Parameters = mergeParameters(params, reqParams, formParams, op.Parameters)
+
+		return nil
 	}
 
-	schema, err := op.ParseAPIObjectSchema("object", typeName(reqer.Req()), fi.ASTFile)
+	schema, err := hd.Schema(reqer.Req())
 	if err != nil {
 		return err
 	}
@@ -152,11 +365,57 @@ func updateRequests(fi funcInfo, op *swag.Operation, h http.Handler, params []sp
 		op.AddParam(spec.BodyParam("body", schema))
 	}
 
-	op.Parameters = mergeParameters(params, op.Parameters)
+	op.Parameters = mergeParameters(params, reqParams, op.Parameters)
 
 	return nil
 }
 
+// contentTypes returns the media types of the codecs registered for the handler.
+func contentTypes(h http.Handler) []string {
+	ct, ok := h.(chai.ContentTyper)
+	if !ok {
+		return []string{"application/json"}
+	}
+
+	return ct.ContentTypes()
+}
+
+// RequestContentTypes returns the media types of the handler's request bodies.
+func RequestContentTypes(h http.Handler) []string {
+	if rct, ok := h.(chai.RequestContentTyper); ok {
+		return rct.RequestContentTypes()
+	}
+
+	return contentTypes(h)
+}
+
+// ProducedTypes returns the media types of the handler's responses.
+func ProducedTypes(h http.Handler, binary bool) []string {
+	if st, ok := h.(chai.Streamer); ok {
+		return st.StreamContentTypes()
+	}
+
+	if bct, ok := h.(chai.BinaryContentTyper); ok && binary {
+		return bct.BinaryContentTypes()
+	}
+
+	return contentTypes(h)
+}
+
+// DecodesBody reports whether the handler decodes the bodies of its requests, see chai.WithoutBody.
+func DecodesBody(h http.Handler) bool {
+	bd, ok := h.(chai.BodyDecoder)
+
+	return !ok || bd.DecodesBody()
+}
+
+// DecodesStrictly reports whether the handler rejects the request bodies with unknown fields, see chai.WithStrictDecoding.
+func DecodesStrictly(h http.Handler) bool {
+	sbd, ok := h.(chai.StrictBodyDecoder)
+
+	return ok && sbd.DecodesStrictly() && DecodesBody(h)
+}
+
 type pk struct {
 	In   string
 	Name string
@@ -231,22 +490,31 @@ func sortedKeys[K comparable, V any](m map[K]V, less func(K, K) bool) []K {
 	return keys
 }
 
-func updateResponses(fi funcInfo, op *swag.Operation, h http.Handler) error {
+func updateResponses(hd *HandlerDocs, h http.Handler, swagger *spec.Swagger) error {
+	if _, ok := h.(chai.WebSocketer); ok {
+		return updateWebSocketResponses(hd, h, swagger)
+	}
+
+	op := hd.Operation
+
 	resErrer, ok := h.(chai.ResErrer)
 	if !ok {
 		return nil
 	}
 
+	resType := chai.BodyType(reflect.TypeOf(resErrer.Res()))
+	binary := chai.IsBinary(chai.BodyType(reflect.TypeOf(resErrer.Res()).Elem()))
+
 	if len(op.Produces) == 0 {
-		op.Produces = append(op.Produces, "application/json")
+		op.Produces = append(op.Produces, ProducedTypes(h, binary)...)
 	}
 
-	resSchema, err := op.ParseAPIObjectSchema("object", typeName(resErrer.Res()), fi.ASTFile)
+	resSchema, err := responseSchema(hd, resType, binary)
 	if err != nil {
 		return err
 	}
 
-	errSchema, err := op.ParseAPIObjectSchema("object", typeName(resErrer.Err()), fi.ASTFile)
+	errSchema, err := errorSchema(hd, h, resErrer.Err(), swagger)
 	if err != nil {
 		return err
 	}
@@ -275,10 +543,208 @@ func updateResponses(fi funcInfo, op *swag.Operation, h http.Handler) error {
 	if noErrors {
 		op.RespondsWith(0, spec.NewResponse().WithSchema(errSchema))
 	}
+	for _, m := range ErrorMappings(h, resErrer.Err()) {
+		if _, ok := responses.StatusCodeResponses[m.Code]; ok {
+			continue
+		}
+
+		op.RespondsWith(m.Code, spec.NewResponse().WithDescription(m.Message).WithSchema(errSchema))
+	}
+
+	addResponseHeaders(op, h, resSchema)
+	describeStream(op, h)
+
+	return nil
+}
+
+// responseSchema returns the schema of the successful responses of type resType. Binary responses are documented as files.
+func responseSchema(hd *HandlerDocs, resType reflect.Type, binary bool) (*spec.Schema, error) {
+	if binary {
+		return &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"file"}}}, nil
+	}
+
+	return hd.Schema(reflect.New(resType).Interface())
+}
+
+// updateWebSocketResponses documents the handshake of WebSocket handlers: the 101 response that upgrades the connection,
+// and the errors that are written before the upgrade. The messages exchanged on the connection are not part of the OpenAPI document.
+func updateWebSocketResponses(hd *HandlerDocs, h http.Handler, swagger *spec.Swagger) error {
+	errer, ok := h.(interface{ Err() any })
+	if !ok {
+		return nil
+	}
+
+	op := hd.Operation
+
+	if len(op.Produces) == 0 {
+		op.Produces = append(op.Produces, "application/json")
+	}
+
+	errSchema, err := errorSchema(hd, h, errer.Err(), swagger)
+	if err != nil {
+		return err
+	}
+
+	if op.Responses == nil {
+		op.Responses = &spec.Responses{}
+	}
+
+	if _, ok := op.Responses.StatusCodeResponses[http.StatusSwitchingProtocols]; !ok {
+		op.RespondsWith(http.StatusSwitchingProtocols, spec.NewResponse().WithDescription("The connection is upgraded to a WebSocket"))
+	}
+
+	if _, ok := op.Responses.StatusCodeResponses[http.StatusUpgradeRequired]; !ok {
+		op.RespondsWith(http.StatusUpgradeRequired, spec.NewResponse().WithDescription("The request is not a WebSocket handshake").WithSchema(errSchema))
+	}
+
+	for _, m := range ErrorMappings(h, errer.Err()) {
+		if _, ok := op.Responses.StatusCodeResponses[m.Code]; ok {
+			continue
+		}
+
+		op.RespondsWith(m.Code, spec.NewResponse().WithDescription(m.Message).WithSchema(errSchema))
+	}
+
+	op.RespondsWith(0, spec.NewResponse().WithSchema(errSchema))
 
 	return nil
 }
 
+var streamDescriptions = map[string]string{
+	chai.EventStreamContentType: "A stream of Server-Sent Events, the data of each event has this schema",
+	chai.NDJSONContentType:      "A stream of items with this schema, one per line as application/x-ndjson or as the elements of a JSON array",
+}
+
+// defaultStreamDescription describes the streams of the media types that streamDescriptions does not know.
+const defaultStreamDescription = "A stream of items with this schema"
+
+// StreamDescription returns the description of the successful responses of a streaming handler, or "" if h does not stream its responses.
+func StreamDescription(h http.Handler) string {
+	st, ok := h.(chai.Streamer)
+	if !ok {
+		return ""
+	}
+
+	if cts := st.StreamContentTypes(); len(cts) > 0 {
+		if description, ok := streamDescriptions[cts[0]]; ok {
+			return description
+		}
+	}
+
+	return defaultStreamDescription
+}
+
+// describeStream describes the successful responses of streaming handlers, whose schema is that of a single element of the stream.
+func describeStream(op *swag.Operation, h http.Handler) {
+	description := StreamDescription(h)
+	if description == "" {
+		return
+	}
+
+	for code, res := range op.Responses.StatusCodeResponses {
+		if code >= http.StatusBadRequest || res.Description != "" {
+			continue
+		}
+
+		res.Description = description
+		op.Responses.StatusCodeResponses[code] = res
+	}
+}
+
+// addResponseHeaders adds the headers declared with chai.WithResponseHeader to the matching responses,
+// adding successful responses that are not documented yet.
+func addResponseHeaders(op *swag.Operation, h http.Handler, resSchema *spec.Schema) {
+	rh, ok := h.(chai.ResponseHeaderer)
+	if !ok {
+		return
+	}
+
+	for _, header := range rh.ResponseHeaders() {
+		codes := []int{header.Code}
+		if header.Code == 0 {
+			codes = successCodes(op.Responses)
+		}
+
+		for _, code := range codes {
+			res, ok := op.Responses.StatusCodeResponses[code]
+			if !ok {
+				if code >= http.StatusBadRequest {
+					continue
+				}
+				res = *spec.NewResponse().WithSchema(resSchema)
+			}
+
+			res.AddHeader(header.Name, spec.ResponseHeader().Typed(header.Type, "").WithDescription(header.Description))
+			op.Responses.StatusCodeResponses[code] = res
+		}
+	}
+}
+
+func successCodes(responses *spec.Responses) []int {
+	res := make([]int, 0)
+
+	for code := range responses.StatusCodeResponses {
+		if code < http.StatusBadRequest {
+			res = append(res, code)
+		}
+	}
+
+	sort.Ints(res)
+
+	return res
+}
+
+// ErrorMappings returns the mappings of the handler's ErrorRegistry that errors of its Err type can match.
+func ErrorMappings(h http.Handler, errPtr any) []chai.ErrorMapping {
+	erer, ok := h.(chai.ErrorRegistryer)
+	if !ok {
+		return nil
+	}
+
+	errType := reflect.TypeOf(errPtr).Elem()
+	res := make([]chai.ErrorMapping, 0)
+
+	for _, m := range erer.ErrorRegistry().Mappings() {
+		if m.CanProduce(errType) {
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+// errorSchema returns the schema of the error responses, which is the handler's Err type
+// unless its error writer wraps every error in a type of its own.
+func errorSchema(hd *HandlerDocs, h http.Handler, errType any, swagger *spec.Swagger) (*spec.Schema, error) {
+	op := hd.Operation
+
+	if ewer, ok := h.(chai.ErrorWriterer); ok {
+		if et, ok := ewer.ErrorWriter().(chai.ErrorTyper); ok {
+			errType = et.ErrorType()
+		}
+	}
+
+	if _, ok := errType.(*chai.Problem); ok {
+		if !contains(op.Produces, "application/problem+json") {
+			op.Produces = append(op.Produces, "application/problem+json")
+		}
+
+		return problemSchema(swagger), nil
+	}
+
+	return hd.Schema(errType)
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 func typeName(i any) string {
 	t := reflect.TypeOf(i)
 
@@ -2,6 +2,7 @@ package openapi2
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -82,6 +83,77 @@ func TestParseAPIObjectSchema(t *testing.T) {
 	}
 }
 
+func TestReflector(t *testing.T) {
+	type want struct {
+		schemaJSON      string
+		definitionsJSON string
+	}
+	tests := []struct {
+		name string
+		val  any
+		want want
+	}{
+		{
+			name: "string",
+			val:  new(string),
+			want: want{schemaJSON: `{"type": "string"}`, definitionsJSON: `{}`},
+		},
+		{
+			name: "error",
+			val:  new(error),
+			want: want{schemaJSON: `{"type": "string"}`, definitionsJSON: `{}`},
+		},
+		{
+			name: "slice of maps",
+			val:  new([]map[string]float64),
+			want: want{schemaJSON: `{"type": "array", "items": {"type": "object", "additionalProperties": {"type": "number", "format": "double"}}}`, definitionsJSON: `{}`},
+		},
+		{
+			name: "struct",
+			val:  new(tests.TestReflected),
+			want: want{
+				schemaJSON: `{"$ref": "#/definitions/tests.TestReflected"}`,
+				definitionsJSON: `{
+					"tests.TestInnerResponse": {
+						"type": "object",
+						"properties": {
+							"bar_bar": {"type": "integer"},
+							"foo_foo": {"type": "integer"}
+						}
+					},
+					"tests.TestReflected": {
+						"type": "object",
+						"required": ["id"],
+						"properties": {
+							"id": {"type": "integer"},
+							"created_at": {"type": "string", "format": "date-time"},
+							"name": {"type": "string", "description": "The name", "example": "foo", "maxLength": 10},
+							"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+							"labels": {"type": "object", "additionalProperties": {"type": "integer"}},
+							"timeout": {"type": "integer", "format": "int64"},
+							"data": {"type": "string", "format": "byte"},
+							"count": {"type": "string"},
+							"parent": {"$ref": "#/definitions/tests.TestReflected"},
+							"inner": {"$ref": "#/definitions/tests.TestInnerResponse"}
+						}
+					}
+				}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definitions := spec.Definitions{}
+
+			schema, err := NewReflector(definitions).Schema(tt.val)
+			require.NoError(t, err)
+
+			require.JSONEq(t, tt.want.schemaJSON, js(schema))
+			require.JSONEq(t, tt.want.definitionsJSON, js(definitions))
+		})
+	}
+}
+
 func TestMergeParameters(t *testing.T) {
 	type args struct {
 		params [][]spec.Parameter
@@ -255,9 +327,53 @@ func TestSortedKeys(t *testing.T) {
 	}
 }
 
+type testStreamer struct {
+	http.Handler
+	contentTypes []string
+}
+
+func (s testStreamer) StreamContentTypes() []string {
+	return s.contentTypes
+}
+
+func TestStreamDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		h    http.Handler
+		want string
+	}{
+		{
+			name: "not a stream",
+			h:    http.NotFoundHandler(),
+			want: "",
+		},
+		{
+			name: "known media type",
+			h:    testStreamer{contentTypes: []string{chai.EventStreamContentType}},
+			want: streamDescriptions[chai.EventStreamContentType],
+		},
+		{
+			name: "unknown media type",
+			h:    testStreamer{contentTypes: []string{"application/x-protobuf"}},
+			want: defaultStreamDescription,
+		},
+		{
+			name: "no media types",
+			h:    testStreamer{},
+			want: defaultStreamDescription,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StreamDescription(tt.h))
+		})
+	}
+}
+
 func TestDocs(t *testing.T) {
 	type args struct {
 		routes []*Route
+		opts   []Option
 	}
 	tests := []struct {
 		name     string
@@ -323,10 +439,337 @@ func TestDocs(t *testing.T) {
 			filePath: "testdata/t2.json",
 			wantErr:  false,
 		},
+		{
+			name: "t3",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test3/{id}",
+						Params: []spec.Parameter{
+							{ParamProps: spec.ParamProps{Name: "id", In: "path", Required: true}, CommonValidations: spec.CommonValidations{Pattern: "^[0-9]+$"}},
+						},
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}),
+					},
+					{
+						Method: "PUT",
+						Path:   "/test3/{id}",
+						Params: []spec.Parameter{
+							{ParamProps: spec.ParamProps{Name: "id", In: "path", Required: true}},
+						},
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}),
+					},
+				},
+			},
+			filePath: "testdata/t3.json",
+			wantErr:  false,
+		},
+		{
+			name: "t4",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "DELETE",
+						Path:   "/test4/{id}",
+						Params: []spec.Parameter{
+							{ParamProps: spec.ParamProps{Name: "id", In: "path", Required: true}},
+						},
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}, chai.WithoutBody()),
+					},
+				},
+			},
+			filePath: "testdata/t4.json",
+			wantErr:  false,
+		},
+		{
+			name: "t5",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test5",
+						Handler: chai.NewReqResHandler(func(req *tests.TestStruct, w http.ResponseWriter, r *http.Request) (*tests.TestStruct, int, error) {
+							return nil, 0, nil
+						}, chai.WithCodecs(chai.JSON, chai.XML, chai.MsgPack)),
+					},
+				},
+			},
+			filePath: "testdata/t5.json",
+			wantErr:  false,
+		},
+		{
+			name: "t6",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test6",
+						Handler: chai.NewReqResHandler(func(req *tests.TestValidatedRequest, w http.ResponseWriter, r *http.Request) (string, int, error) {
+							return "", 0, nil
+						}),
+					},
+				},
+			},
+			filePath: "testdata/t6.json",
+			wantErr:  false,
+		},
+		{
+			name: "t7",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test7",
+
+						// @Success      200
+						// @Failure      404,500
+						Handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, *tests.TestErrorPtr) {
+							return nil, 0, nil
+						}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+					},
+				},
+			},
+			filePath: "testdata/t7.json",
+			wantErr:  false,
+		},
+		{
+			name: "t8",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test8",
+
+						// @Success      200
+						// @Failure      400
+						Handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}, chai.WithErrors(testErrors())),
+					},
+					{
+						Method: "GET",
+						Path:   "/test8/typed",
+						Handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, *tests.TestErrorPtr) {
+							return nil, 0, nil
+						}, chai.WithErrors(testErrors())),
+					},
+				},
+			},
+			filePath: "testdata/t8.json",
+			wantErr:  false,
+		},
+		{
+			name: "t9",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test9",
+						Handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}, chai.WithStrictDecoding()),
+					},
+					{
+						Method: "PUT",
+						Path:   "/test9",
+						Handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}),
+					},
+				},
+			},
+			filePath: "testdata/t9.json",
+			wantErr:  false,
+		},
+		{
+			name: "t10",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test10",
+
+						// @Success      201,202
+						Handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (chai.Response[*tests.TestResponse], int, error) {
+							return chai.Response[*tests.TestResponse]{}, 0, nil
+						}, chai.WithResponseHeader(http.StatusCreated, "Location", "The URL of the created resource"), chai.WithResponseHeader(0, "X-Request-Id", "The ID of the request")),
+					},
+				},
+			},
+			filePath: "testdata/t10.json",
+			wantErr:  false,
+		},
+		{
+			name: "t11",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test11/{topic}",
+						Handler: chai.NewSSEHandler(func(req *tests.TestEventsRequest, w http.ResponseWriter, r *http.Request) (<-chan chai.Event[*tests.TestEvent], int, error) {
+							return nil, 0, nil
+						}),
+					},
+				},
+			},
+			filePath: "testdata/t11.json",
+			wantErr:  false,
+		},
+		{
+			name: "t12",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test12",
+						Handler: chai.NewStreamHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (<-chan *tests.TestEvent, int, error) {
+							return nil, 0, nil
+						}, chai.WithoutBody()),
+					},
+				},
+			},
+			filePath: "testdata/t12.json",
+			wantErr:  false,
+		},
+		{
+			name: "t13",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test13/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestUploadRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}, chai.WithMultipart()),
+					},
+					{
+						Method: "POST",
+						Path:   "/test13/stream",
+						Handler: chai.NewReqResHandler(func(req *tests.TestStreamingUploadRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}, chai.WithMultipart()),
+					},
+				},
+			},
+			filePath: "testdata/t13.json",
+			wantErr:  false,
+		},
+		{
+			name: "t14",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test14/report",
+						Handler: chai.NewResHandler(func(w http.ResponseWriter, r *http.Request) (chai.File, int, error) {
+							return chai.File{}, 0, nil
+						}, chai.WithBinaryContentTypes("application/pdf")),
+					},
+					{
+						Method: "GET",
+						Path:   "/test14/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) ([]byte, int, error) {
+							return nil, 0, nil
+						}, chai.WithoutBody()),
+					},
+					{
+						Method: "POST",
+						Path:   "/test14",
+						Handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*chai.Response[*chai.File], int, error) {
+							return nil, 0, nil
+						}, chai.WithBinaryContentTypes("image/png", "image/jpeg")),
+					},
+				},
+			},
+			filePath: "testdata/t14.json",
+			wantErr:  false,
+		},
+		{
+			name: "t15",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test15/{topic}",
+						Handler: chai.NewWebSocketHandler(func(req *tests.TestEventsRequest, conn *chai.WebSocketConn[*tests.TestRequest, *tests.TestEvent], r *http.Request) error {
+							return nil
+						}, chai.WithErrors(testErrors())),
+					},
+				},
+			},
+			filePath: "testdata/t15.json",
+			wantErr:  false,
+		},
+		{
+			name: "t16",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test16/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (*tests.TestReflected, int, error) {
+							return nil, 0, nil
+						}, chai.WithErrors(testErrors())),
+					},
+				},
+				opts: []Option{WithReflection()},
+			},
+			filePath: "testdata/t16.json",
+			wantErr:  false,
+		},
+		{
+			name: "t17",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test17/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (*tests.TestInnerResponse, int, error) {
+							return nil, 0, nil
+						}),
+						Middlewares: []func(http.Handler) http.Handler{testAuth, testLogger},
+					},
+				},
+				opts: []Option{WithReflection()},
+			},
+			filePath: "testdata/t17.json",
+			wantErr:  false,
+		},
+		{
+			name: "t18",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test18/{id}",
+						Params: []spec.Parameter{
+							*spec.PathParam("id").Typed("integer", ""),
+							*spec.QueryParam("q").Typed("string", "").WithEnum("a", "b").AsRequired(),
+							*spec.HeaderParam("X-Foo").Typed("string", "").WithPattern("^[a-z]+$").AsRequired(),
+						},
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (*tests.TestInnerResponse, int, error) {
+							return nil, 0, nil
+						}),
+						Name:    "create-test18",
+						Host:    "{tenant:[a-z]+}.example.com",
+						Schemes: []string{"https"},
+					},
+				},
+				opts: []Option{WithReflection()},
+			},
+			filePath: "testdata/t18.json",
+			wantErr:  false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Docs(tt.args.routes)
+			got, err := Docs(tt.args.routes, tt.args.opts...)
 
 			LogJSON(got)
 
@@ -339,8 +782,74 @@ func TestDocs(t *testing.T) {
 	}
 }
 
+var testAuth = chai.DocumentMiddleware(func(next http.Handler) http.Handler {
+	return next
+}, chai.MiddlewareDocs{
+	Headers:   []chai.MiddlewareHeader{{Name: "X-Api-Key", Description: "The API key", Required: true}, {Name: "Authorization", Description: "ignored"}},
+	Security:  []map[string][]string{{"ApiKeyAuth": {}}},
+	Responses: map[int]string{http.StatusUnauthorized: "Unauthorized", http.StatusTooManyRequests: "Too Many Requests"},
+	Tags:      []string{"admin"},
+})
+
+func testLogger(next http.Handler) http.Handler {
+	return next
+}
+
+func testErrors() *chai.ErrorRegistry {
+	er := chai.NewErrorRegistry().Register(errors.New("not found"), http.StatusNotFound, "")
+
+	return chai.RegisterErrorType[*tests.TestErrorPtr](er, http.StatusConflict, "the account is locked")
+}
+
 func load(t *testing.T, path string) string {
 	b, err := ioutil.ReadFile(path)
 	require.NoError(t, err)
 	return string(b)
 }
+
+type methoderFunc func(method, pattern string, h http.Handler)
+
+func (fn methoderFunc) Method(method, pattern string, h http.Handler) {
+	fn(method, pattern, h)
+}
+
+func TestRecorder(t *testing.T) {
+	registered := map[string]http.Handler{}
+	rec := NewRecorder(methoderFunc(func(method, pattern string, h http.Handler) {
+		registered[method+" "+pattern] = h
+	}), nil)
+
+	var calls []string
+	mw := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "mw")
+			h.ServeHTTP(w, r)
+		})
+	}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "h")
+	})
+
+	rec.Method(http.MethodGet, "/items", h)
+	rec.With(mw).Method(http.MethodGet, "/items/{id:^[0-9]+$}", h)
+
+	routes, err := Routes(rec)
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+
+	require.Equal(t, "/items", routes[0].Path)
+	require.Empty(t, routes[0].Middlewares)
+
+	require.Equal(t, http.MethodGet, routes[1].Method)
+	require.Equal(t, "/items/{id}", routes[1].Path)
+	require.Len(t, routes[1].Params, 1)
+	require.Equal(t, "integer", routes[1].Params[0].Type)
+	require.Len(t, routes[1].Middlewares, 1)
+
+	registered["GET /items/{id:^[0-9]+$}"].ServeHTTP(nil, nil)
+	require.Equal(t, []string{"mw", "h"}, calls)
+
+	_, err = Docs(routes)
+	require.NoError(t, err)
+}
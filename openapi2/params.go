@@ -1,2 +1,178 @@
 package openapi2
 
+import (
+	"encoding"
+	"reflect"
+	"time"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-openapi/spec"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// tagParams returns the non-body parameters declared with `path`, `query` and `header` struct tags on the request type.
+// Cookie parameters cannot be expressed in Swagger 2.0 and are skipped.
+func tagParams(t reflect.Type) ([]spec.Parameter, error) {
+	return fieldParams(t, func(in string) bool { return in != chai.InCookie })
+}
+
+// CookieParams returns the parameters declared with `cookie` struct tags on the request type, which OpenAPI 3 documents can express.
+func CookieParams(t reflect.Type) ([]spec.Parameter, error) {
+	return fieldParams(t, func(in string) bool { return in == chai.InCookie })
+}
+
+// fieldParams returns the parameters declared with struct tags on the request type whose location is accepted by in.
+func fieldParams(t reflect.Type, in func(string) bool) ([]spec.Parameter, error) {
+	res := make([]spec.Parameter, 0)
+
+	for _, pf := range chai.ParamFields(t) {
+		if !in(pf.In) {
+			continue
+		}
+
+		p := spec.Parameter{
+			ParamProps: spec.ParamProps{
+				Name:        pf.Name,
+				In:          pf.In,
+				Description: pf.Field.Tag.Get("description"),
+				Required:    pf.In == chai.InPath,
+			},
+			SimpleSchema: simpleSchema(pf.Field.Type),
+		}
+
+		if format := pf.Field.Tag.Get("format"); format != "" {
+			p.Format = format
+		}
+
+		if p.Type == "array" {
+			p.CollectionFormat = "csv"
+			if pf.In == chai.InQuery {
+				p.CollectionFormat = "multi"
+			}
+		}
+
+		rules, err := chai.FieldRules(pf.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, paramRules(p, rules))
+	}
+
+	return res, nil
+}
+
+// formDataParams returns the formData parameters of a request type that is decoded from multipart/form-data, see chai.MultipartFields.
+func formDataParams(t reflect.Type) ([]spec.Parameter, error) {
+	fields, err := chai.MultipartFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]spec.Parameter, 0)
+
+	for _, mf := range fields {
+		p := spec.Parameter{
+			ParamProps: spec.ParamProps{
+				Name:        mf.Name,
+				In:          "formData",
+				Description: mf.Field.Tag.Get("description"),
+			},
+		}
+
+		if mf.Kind != chai.MultipartValue {
+			p.Type = "file"
+		} else {
+			p.SimpleSchema = simpleSchema(mf.Field.Type)
+			if p.Type == "array" {
+				p.CollectionFormat = "multi"
+			}
+		}
+
+		if format := mf.Field.Tag.Get("format"); format != "" && p.Type != "file" {
+			p.Format = format
+		}
+
+		rules, err := chai.FieldRules(mf.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Type == "file" {
+			p.Required = rules.Required
+		} else {
+			p = paramRules(p, rules)
+		}
+
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+// inheritPatterns copies the regex patterns, the enums and the required flags of the route's params onto the matching params declared on the request type.
+func inheritPatterns(params []spec.Parameter, routeParams []spec.Parameter) []spec.Parameter {
+	for i := range params {
+		for _, rp := range routeParams {
+			if rp.In != params[i].In || rp.Name != params[i].Name {
+				continue
+			}
+
+			if params[i].Pattern == "" {
+				params[i].Pattern = rp.Pattern
+			}
+
+			// The router only matches the requests that have the parameter, e.g. for the query matchers of gorilla/mux.
+			if rp.Required {
+				params[i].Required = true
+			}
+
+			if len(params[i].Enum) == 0 {
+				params[i].Enum = rp.Enum
+			}
+		}
+	}
+
+	return params
+}
+
+func simpleSchema(t reflect.Type) spec.SimpleSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return spec.SimpleSchema{Type: "string", Format: "date-time"}
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return spec.SimpleSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return spec.SimpleSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return spec.SimpleSchema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return spec.SimpleSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return spec.SimpleSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return spec.SimpleSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return spec.SimpleSchema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		items := simpleSchema(t.Elem())
+		return spec.SimpleSchema{
+			Type: "array",
+			Items: &spec.Items{
+				SimpleSchema: items,
+			},
+		}
+	default:
+		return spec.SimpleSchema{Type: "string"}
+	}
+}
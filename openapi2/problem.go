@@ -0,0 +1,37 @@
+package openapi2
+
+import (
+	"github.com/go-openapi/spec"
+)
+
+const problemDefinition = "chai.Problem"
+
+// problemSchema adds the RFC 7807 problem details definition to the spec and returns a reference to it.
+func problemSchema(swagger *spec.Swagger) *spec.Schema {
+	if swagger.Definitions == nil {
+		swagger.Definitions = make(spec.Definitions)
+	}
+
+	if _, ok := swagger.Definitions[problemDefinition]; !ok {
+		swagger.Definitions[problemDefinition] = spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"object"},
+				Properties: spec.SchemaProperties{
+					"type":     *spec.StringProperty().WithDescription("A URI reference that identifies the problem type").WithExample("about:blank"),
+					"title":    *spec.StringProperty().WithDescription("A short, human-readable summary of the problem type").WithExample("Not Found"),
+					"status":   *spec.Int64Property().WithDescription("The HTTP status code").WithExample(404),
+					"detail":   *spec.StringProperty().WithDescription("A human-readable explanation specific to this occurrence of the problem"),
+					"instance": *spec.StringProperty().WithDescription("A URI reference that identifies the specific occurrence of the problem"),
+				},
+				AdditionalProperties: &spec.SchemaOrBool{Allows: true},
+			},
+		}
+	}
+
+	return spec.RefSchema("#/definitions/" + problemDefinition)
+}
+
+// IsProblem reports whether the schema refers to the problem details definition.
+func IsProblem(schema *spec.Schema) bool {
+	return schema != nil && schema.Ref.String() == "#/definitions/"+problemDefinition
+}
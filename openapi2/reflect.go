@@ -0,0 +1,260 @@
+package openapi2
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/internal/docgen"
+	"github.com/go-openapi/spec"
+)
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Reflector builds the schemas of Go types by reflection, so that documents can be generated without the Go source,
+// e.g. by deployed binaries. The named struct types are added to the definitions and referred to by name.
+//
+// The fields of structs are named by their `json` tags, and embedded structs without one are flattened like encoding/json does.
+// The `description`, `example`, `format`, `default` and `extensions` tags are used like swag does, and the validation rules
+// of the `validate` and `enums` tags are applied, see chai.FieldRules. Fields tagged with `path`, `query`, `header` or `cookie` are parameters,
+// not properties.
+type Reflector struct {
+	definitions spec.Definitions
+}
+
+func NewReflector(definitions spec.Definitions) *Reflector {
+	return &Reflector{definitions: definitions}
+}
+
+// Schema returns the schema of the type v points to.
+func (r *Reflector) Schema(v any) (*spec.Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return &spec.Schema{}, nil
+	}
+
+	return r.TypeSchema(t)
+}
+
+// TypeSchema returns the schema of t, or of the type t points to. It fails if the validation rules of a struct field do not parse.
+func (r *Reflector) TypeSchema(t reflect.Type) (*spec.Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return spec.DateTimeProperty(), nil
+	case t == durationType:
+		return spec.Int64Property(), nil
+	case t == rawMessageType:
+		return &spec.Schema{}, nil
+	case t == errorType:
+		return spec.StringProperty(), nil
+	case t.Kind() != reflect.Struct && reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &spec.Schema{}, nil
+	case reflect.PointerTo(t).Implements(textMarshalerType):
+		return spec.StringProperty(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return spec.BoolProperty(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}}}, nil
+	case reflect.Int32, reflect.Uint32:
+		return spec.Int32Property(), nil
+	case reflect.Int64, reflect.Uint64:
+		return spec.Int64Property(), nil
+	case reflect.Float32:
+		return spec.Float32Property(), nil
+	case reflect.Float64:
+		return spec.Float64Property(), nil
+	case reflect.String:
+		return spec.StringProperty(), nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Format: "byte"}}, nil
+		}
+
+		items, err := r.TypeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return spec.ArrayProperty(items), nil
+	case reflect.Map:
+		values, err := r.TypeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return spec.MapProperty(values), nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+
+		name := DefinitionName(t)
+		if _, ok := r.definitions[name]; !ok {
+			// The definition is added before its properties are built, so that recursive types refer to it.
+			r.definitions[name] = spec.Schema{}
+
+			s, err := r.structSchema(t)
+			if err != nil {
+				delete(r.definitions, name)
+				return nil, err
+			}
+
+			r.definitions[name] = *s
+		}
+
+		return spec.RefSchema("#/definitions/" + name), nil
+	default:
+		return &spec.Schema{}, nil
+	}
+}
+
+func (r *Reflector) structSchema(t reflect.Type) (*spec.Schema, error) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, Properties: spec.SchemaProperties{}}}
+
+	err := r.addFields(s, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// addFields adds the properties of the fields of the struct type t to s.
+func (r *Reflector) addFields(s *spec.Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if (name == "-" && opts == "") || docgen.IsParam(f) {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if err := r.addFields(s, ft); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := spec.StringProperty()
+		if !strings.Contains(opts, "string") {
+			var err error
+			prop, err = r.TypeSchema(f.Type)
+			if err != nil {
+				return err
+			}
+		}
+
+		rules, err := chai.FieldRules(f)
+		if err != nil {
+			return err
+		}
+
+		if rules.Required {
+			s.Required = append(s.Required, name)
+		}
+
+		if prop.Ref.String() == "" {
+			fieldTags(prop, f)
+			schemaRules(prop, rules)
+		}
+
+		s.Properties[name] = *prop
+	}
+
+	return nil
+}
+
+// fieldTags applies the swag tags of f to its schema.
+func fieldTags(s *spec.Schema, f reflect.StructField) {
+	if d := f.Tag.Get("description"); d != "" {
+		s.Description = d
+	}
+
+	if format := f.Tag.Get("format"); format != "" {
+		s.Format = format
+	}
+
+	itemType := schemaType(s)
+	if itemType == "array" && s.Items != nil && s.Items.Schema != nil {
+		itemType = schemaType(s.Items.Schema)
+	}
+
+	if example, ok := docgen.Example(f, schemaType(s), itemType); ok {
+		s.Example = example
+	}
+
+	if def, ok := docgen.Default(f, schemaType(s)); ok {
+		s.Default = def
+	}
+
+	for _, ext := range docgen.Extensions(f) {
+		s.AddExtension(ext.Name, ext.Value)
+	}
+}
+
+// schemaRules applies the validation rules of a field to its schema.
+func schemaRules(s *spec.Schema, rules *chai.Rules) {
+	switch schemaType(s) {
+	case "string":
+		s.MinLength = toInt64(rules.Min)
+		s.MaxLength = toInt64(rules.Max)
+	case "array":
+		s.MinItems = toInt64(rules.Min)
+		s.MaxItems = toInt64(rules.Max)
+	case "integer", "number":
+		s.Minimum = rules.Min
+		s.Maximum = rules.Max
+	}
+
+	// The values of arrays are those of their items.
+	enumSchema := s
+	if schemaType(s) == "array" && s.Items != nil && s.Items.Schema != nil {
+		enumSchema = s.Items.Schema
+	}
+
+	enumSchema.Enum = append(enumSchema.Enum, docgen.Enum(rules, schemaType(enumSchema))...)
+
+	if rules.Format != "" && s.Format == "" {
+		s.Format = rules.Format
+	}
+
+	applyRules(s, rules)
+}
+
+func schemaType(s *spec.Schema) string {
+	if len(s.Type) == 0 {
+		return ""
+	}
+
+	return s.Type[0]
+}
@@ -0,0 +1,211 @@
+package openapi2
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-openapi/spec"
+)
+
+// RouteWalker is implemented by routers that can list their routes, so that they can be documented without an adapter package.
+type RouteWalker interface {
+	WalkRoutes(fn WalkFunc) error
+}
+
+// WalkFunc is called for each route of a RouteWalker. Walking stops at the first error it returns.
+type WalkFunc func(route *Route) error
+
+// Routes returns the routes of w, in the order it walks them.
+func Routes(w RouteWalker) ([]*Route, error) {
+	routes := make([]*Route, 0)
+
+	err := w.WalkRoutes(func(route *Route) error {
+		routes = append(routes, route)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// PathParser parses a route pattern into the parameters of its path and the path as it is written in the documents.
+type PathParser func(pattern string) ([]spec.Parameter, string)
+
+// Recorder is a chai.Methoder that records the routes registered through it before passing them on to the router it wraps,
+// so that the routes of any router with a Method(method, pattern, h) method can be documented.
+//
+// The middlewares of the routes are recorded with With only: the routes registered on the sub-routers, groups or mounted routers
+// of the wrapped router, e.g. with chi's Route, Group or Mount, are not recorded, since the recorder has no equivalent of them.
+// The chi and gorilla/mux routers do not implement RouteWalker, and are documented with the chi and gorilla packages instead.
+type Recorder struct {
+	methoder    chai.Methoder
+	parse       PathParser
+	middlewares []func(http.Handler) http.Handler
+	recording   *recording
+}
+
+type recording struct {
+	mu     sync.Mutex
+	routes []*Route
+}
+
+// NewRecorder returns a Recorder that registers the routes on m. The patterns are parsed with parse, or ParsePathParams if it is nil.
+func NewRecorder(m chai.Methoder, parse PathParser) *Recorder {
+	if parse == nil {
+		parse = ParsePathParams
+	}
+
+	return &Recorder{methoder: m, parse: parse, recording: &recording{}}
+}
+
+// Method registers h, wrapped in the middlewares of the recorder, on the wrapped router, and records the route.
+func (r *Recorder) Method(method, pattern string, h http.Handler) {
+	wrapped := h
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		wrapped = r.middlewares[i](wrapped)
+	}
+
+	r.methoder.Method(method, pattern, wrapped)
+
+	params, path := r.parse(pattern)
+
+	r.recording.mu.Lock()
+	defer r.recording.mu.Unlock()
+
+	r.recording.routes = append(r.recording.routes, &Route{
+		Method:      method,
+		Path:        path,
+		Params:      params,
+		Handler:     h,
+		Middlewares: r.middlewares,
+	})
+}
+
+// With returns a Recorder that records to the same routes as r, and whose handlers are wrapped in the middlewares of r and then middlewares.
+func (r *Recorder) With(middlewares ...func(http.Handler) http.Handler) *Recorder {
+	mws := make([]func(http.Handler) http.Handler, 0, len(r.middlewares)+len(middlewares))
+	mws = append(mws, r.middlewares...)
+	mws = append(mws, middlewares...)
+
+	return &Recorder{methoder: r.methoder, parse: r.parse, middlewares: mws, recording: r.recording}
+}
+
+// Config returns the Config of the wrapped router, so that the handlers registered through the recorder inherit its options.
+func (r *Recorder) Config() *chai.Config {
+	return chai.ConfigOf(r.methoder)
+}
+
+// WalkRoutes calls fn for the recorded routes, in the order they were registered.
+func (r *Recorder) WalkRoutes(fn WalkFunc) error {
+	r.recording.mu.Lock()
+	routes := append([]*Route{}, r.recording.routes...)
+	r.recording.mu.Unlock()
+
+	for _, route := range routes {
+		if err := fn(route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var integerSchema = spec.SimpleSchema{Type: "integer"}
+var numberSchema = spec.SimpleSchema{Type: "number"}
+
+// RegexPatternSchemas are the types of the path parameters whose regular expressions match them, e.g. {id:^[0-9]+$}.
+var RegexPatternSchemas = map[string]spec.SimpleSchema{
+	"/^(0|-*[1-9]+[0-9]*)$/":  integerSchema,
+	"^[0-9]+$":                integerSchema,
+	"[+-]?([0-9]*[.])?[0-9]+": numberSchema,
+}
+
+// ParsePathParams parses the "{name}" and "{name:regexp}" parameters of a pattern, and returns them with the pattern without their regular expressions.
+// The parameters are typed with RegexPatternSchemas.
+func ParsePathParams(path string) ([]spec.Parameter, string) {
+	return ParsePathParamsWith(path, RegexPatternSchemas)
+}
+
+// ParsePathParamsWith parses the parameters of a pattern like ParsePathParams, and types them with schemas,
+// e.g. for routers that keep their own regular expression types.
+func ParsePathParamsWith(path string, schemas map[string]spec.SimpleSchema) ([]spec.Parameter, string) {
+	res := make([]spec.Parameter, 0)
+	regexlessPath := ""
+
+	for {
+		param, before, after := nextParam(path, schemas)
+		regexlessPath += before
+
+		if param == nil {
+			break
+		}
+
+		regexlessPath += "{" + param.Name + "}"
+
+		res = append(res, *param)
+		path = after
+	}
+
+	return res, regexlessPath
+}
+
+func nextParam(pattern string, schemas map[string]spec.SimpleSchema) (param *spec.Parameter, before string, after string) {
+	before, after, found := strings.Cut(pattern, "{")
+	if !found {
+		return nil, before, after
+	}
+
+	// Read to closing } taking into account opens and closes in curl count (cc)
+	cc := 1
+	pe := 0
+
+	for i, c := range after {
+		if c == '{' {
+			cc++
+		} else if c == '}' {
+			cc--
+
+			if cc == 0 {
+				pe = i
+				break
+			}
+		}
+	}
+
+	key := after[:pe]
+	after = after[pe+1:]
+
+	key, rexpat, _ := strings.Cut(key, ":")
+
+	if len(rexpat) > 0 {
+		if rexpat[0] != '^' {
+			rexpat = "^" + rexpat
+		}
+		if rexpat[len(rexpat)-1] != '$' {
+			rexpat += "$"
+		}
+	}
+
+	schema, ok := schemas[rexpat]
+	if !ok {
+		schema = spec.SimpleSchema{
+			Type: "string",
+		}
+	}
+
+	return &spec.Parameter{
+		CommonValidations: spec.CommonValidations{
+			Pattern: rexpat,
+		},
+		ParamProps: spec.ParamProps{
+			Name:     key,
+			In:       "path",
+			Required: true,
+		},
+		SimpleSchema: schema,
+	}, before, after
+}
@@ -0,0 +1,240 @@
+package openapi2
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-openapi/spec"
+)
+
+// addValidationRules adds the validation rules that swag does not parse from struct tags (pattern and len)
+// to the definitions of t and of the struct types it references.
+func addValidationRules(definitions spec.Definitions, t reflect.Type) error {
+	return walkStructs(t, map[reflect.Type]bool{}, func(st reflect.Type) error {
+		def, ok := definitions[definitionName(st)]
+		if !ok {
+			return nil
+		}
+
+		for i := 0; i < st.NumField(); i++ {
+			f := st.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" {
+				name = f.Name
+			}
+
+			prop, ok := def.Properties[name]
+			if !ok {
+				continue
+			}
+
+			rules, err := chai.FieldRules(f)
+			if err != nil {
+				return err
+			}
+
+			applyRules(&prop, rules)
+			def.Properties[name] = prop
+		}
+
+		definitions[definitionName(st)] = def
+
+		return nil
+	})
+}
+
+// strictBody points the body parameter of op to copies of the definitions of t and of the struct types it references,
+// named with a Strict suffix and marked with `additionalProperties: false`, for handlers that reject unknown fields.
+// The definitions themselves are left as is, since the responses and the other routes may use the same types.
+func strictBody(definitions spec.Definitions, op *spec.Operation, t reflect.Type) error {
+	names := make(map[string]string)
+
+	err := walkStructs(t, map[reflect.Type]bool{}, func(st reflect.Type) error {
+		if _, ok := definitions[definitionName(st)]; ok {
+			names[definitionName(st)] = definitionName(st) + "Strict"
+		}
+
+		return nil
+	})
+	if err != nil || len(names) == 0 {
+		return err
+	}
+
+	for name, strict := range names {
+		def, err := copySchema(definitions[name])
+		if err != nil {
+			return err
+		}
+
+		def.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+		strictRefs(&def, names)
+		definitions[strict] = def
+	}
+
+	for i, p := range op.Parameters {
+		if p.In != "body" || p.Schema == nil {
+			continue
+		}
+
+		schema, err := copySchema(*p.Schema)
+		if err != nil {
+			return err
+		}
+
+		strictRefs(&schema, names)
+		op.Parameters[i].Schema = &schema
+	}
+
+	return nil
+}
+
+// copySchema returns a deep copy of s, whose properties can be changed without changing those of s.
+func copySchema(s spec.Schema) (spec.Schema, error) {
+	var res spec.Schema
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return res, err
+	}
+
+	return res, json.Unmarshal(b, &res)
+}
+
+// strictRefs replaces the references of s to the definitions that are keys of names with references to their strict copies.
+func strictRefs(s *spec.Schema, names map[string]string) {
+	if s == nil {
+		return
+	}
+
+	if strict, ok := names[strings.TrimPrefix(s.Ref.String(), "#/definitions/")]; ok {
+		s.Ref = spec.MustCreateRef("#/definitions/" + strict)
+	}
+
+	for name, prop := range s.Properties {
+		strictRefs(&prop, names)
+		s.Properties[name] = prop
+	}
+
+	if s.Items != nil {
+		strictRefs(s.Items.Schema, names)
+		for i := range s.Items.Schemas {
+			strictRefs(&s.Items.Schemas[i], names)
+		}
+	}
+
+	for i := range s.AllOf {
+		strictRefs(&s.AllOf[i], names)
+	}
+
+	if s.AdditionalProperties != nil {
+		strictRefs(s.AdditionalProperties.Schema, names)
+	}
+}
+
+func applyRules(s *spec.Schema, rules *chai.Rules) {
+	if rules.Pattern != nil && s.Pattern == "" {
+		s.Pattern = rules.Pattern.String()
+	}
+
+	if rules.Len != nil {
+		n := int64(*rules.Len)
+		if s.Type.Contains("array") {
+			s.MinItems, s.MaxItems = &n, &n
+		} else {
+			s.MinLength, s.MaxLength = &n, &n
+		}
+	}
+}
+
+// paramRules returns p with the validation rules declared on its field.
+func paramRules(p spec.Parameter, rules *chai.Rules) spec.Parameter {
+	if rules.Required {
+		p.Required = true
+	}
+
+	if p.Type == "string" {
+		p.MinLength = toInt64(rules.Min)
+		p.MaxLength = toInt64(rules.Max)
+	} else if p.Type == "array" {
+		p.MinItems = toInt64(rules.Min)
+		p.MaxItems = toInt64(rules.Max)
+	} else {
+		p.Minimum = rules.Min
+		p.Maximum = rules.Max
+	}
+
+	if rules.Len != nil {
+		n := int64(*rules.Len)
+		if p.Type == "array" {
+			p.MinItems, p.MaxItems = &n, &n
+		} else {
+			p.MinLength, p.MaxLength = &n, &n
+		}
+	}
+
+	for _, e := range rules.Enum {
+		p.Enum = append(p.Enum, e)
+	}
+
+	if rules.Pattern != nil {
+		p.Pattern = rules.Pattern.String()
+	}
+
+	if rules.Format != "" {
+		p.Format = rules.Format
+	}
+
+	return p
+}
+
+func toInt64(f *float64) *int64 {
+	if f == nil {
+		return nil
+	}
+
+	n := int64(*f)
+
+	return &n
+}
+
+// definitionName returns the name swag gives to the definition of a named type.
+func definitionName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+var typeArgPkgPathExpr = regexp.MustCompile(`[\w.\-]+/`)
+
+// DefinitionName returns the name of the definition of a named type, which is the one swag gives it.
+// The type arguments of generic types are named by their package name rather than their package path.
+func DefinitionName(t reflect.Type) string {
+	return typeArgPkgPathExpr.ReplaceAllString(definitionName(t), "")
+}
+
+func walkStructs(t reflect.Type, seen map[reflect.Type]bool, fn func(reflect.Type) error) error {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	if t.Name() != "" {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if err := walkStructs(t.Field(i).Type, seen, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
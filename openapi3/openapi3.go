@@ -1,14 +1,16 @@
 package openapi3
 
 import (
-	kinopenapi2 "github.com/getkin/kin-openapi/openapi2"
-	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/ghodss/yaml"
 	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/swag"
 	"github.com/go-chai/swag/gen"
+	"github.com/go-openapi/spec"
 )
 
+// Version is the version of the OpenAPI specification the documents are written in.
+const Version = "3.0.3"
+
 type GenConfig = gen.GenConfig
 
 func WriteDocs(docs *openapi3.T, cfg *GenConfig) error {
@@ -17,19 +19,135 @@ func WriteDocs(docs *openapi3.T, cfg *GenConfig) error {
 
 type Route = openapi2.Route
 
-func Docs(routes []*Route) (*openapi3.T, error) {
-	docs, err := openapi2.Docs(routes)
-	if err != nil {
-		return nil, err
+// Docs documents the routes in an OpenAPI 3 document. The metadata of the operations, and the parameters, bodies and responses
+// declared with swaggo annotations, are parsed from the annotations, and the rest is built from the types of the handlers,
+// so that the operations can use the features of OpenAPI 3 that Swagger 2 lacks, e.g. cookie parameters, request bodies
+// and responses with several media types, oneOf and anyOf schemas, links and callbacks. The schemas of the types are built
+// by reflection, see Reflector, and described by the comments that swag parses from the Go source, unless openapi2.WithReflection is set.
+func Docs(routes []*Route, opts ...openapi2.Option) (*openapi3.T, error) {
+	parser := openapi2.NewParser()
+	docs := New()
+
+	for _, route := range routes {
+		if openapi2.Undocumented(route) {
+			continue
+		}
+
+		err := RegisterRoute(parser, docs, route, opts...)
+		if err != nil {
+			return nil, err
+		}
 	}
-	docsJSON, err := openapi2.MarshalYAML(docs)
+
+	docs.Info = info(parser.GetSwagger().Info)
+
+	addDefinitions(docs, parser.GetSwagger().Definitions)
+
+	return docs, nil
+}
+
+// New returns an empty document.
+func New() *openapi3.T {
+	return &openapi3.T{
+		OpenAPI:    Version,
+		Info:       &openapi3.Info{},
+		Paths:      openapi3.Paths{},
+		Components: openapi3.NewComponents(),
+	}
+}
+
+// RegisterRoute adds the operation of the route to docs, and the schemas of its types to the components of docs.
+// The definitions that its annotations refer to are added to the parser's swagger, and are added to the components of docs by Docs.
+func RegisterRoute(parser *swag.Parser, docs *openapi3.T, route *Route, opts ...openapi2.Option) error {
+	hd, err := openapi2.ParseAnnotations(parser, route, opts...)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if docs.Components.Schemas == nil {
+		docs.Components.Schemas = openapi3.Schemas{}
+	}
+
+	g := &generator{
+		parser:    parser,
+		hd:        hd,
+		route:     route,
+		h:         route.Handler,
+		reflector: NewReflector(docs.Components.Schemas),
+		opts:      opts,
 	}
-	kinOpenAPI2 := new(kinopenapi2.T)
-	err = yaml.Unmarshal(docsJSON, kinOpenAPI2)
+
+	op, err := g.operation()
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	op.Servers = servers(route)
+
+	docs.AddOperation(route.Path, route.Method, op)
+
+	return nil
+}
+
+// servers returns the servers of the host the route is restricted to, one for each of its schemes, or nil if it is not restricted to a host.
+// The variables of the host template default to their names.
+func servers(route *Route) *openapi3.Servers {
+	if route.Host == "" {
+		return nil
+	}
+
+	params, host := openapi2.ParsePathParams(route.Host)
+
+	var variables map[string]*openapi3.ServerVariable
+	for _, p := range params {
+		if variables == nil {
+			variables = map[string]*openapi3.ServerVariable{}
+		}
+
+		v := &openapi3.ServerVariable{Default: p.Name}
+		if p.Pattern != "" {
+			v.Description = "Matches " + p.Pattern
+		}
+		variables[p.Name] = v
+	}
+
+	schemes := route.Schemes
+	if len(schemes) == 0 {
+		// Scheme-relative URLs use the scheme of the document.
+		schemes = []string{""}
+	}
+
+	res := openapi3.Servers{}
+	for _, scheme := range schemes {
+		url := "//" + host
+		if scheme != "" {
+			url = scheme + ":" + url
+		}
+
+		res = append(res, &openapi3.Server{URL: url, Variables: variables})
 	}
-	return openapi2conv.ToV3(kinOpenAPI2)
+
+	return &res
+}
+
+func info(i *spec.Info) *openapi3.Info {
+	res := &openapi3.Info{}
+	if i == nil {
+		return res
+	}
+
+	res.Title = i.Title
+	res.Description = i.Description
+	res.TermsOfService = i.TermsOfService
+	res.Version = i.Version
+
+	if i.Contact != nil {
+		res.Contact = &openapi3.Contact{Name: i.Contact.Name, URL: i.Contact.URL, Email: i.Contact.Email}
+	}
+
+	if i.License != nil {
+		res.License = &openapi3.License{Name: i.License.Name, URL: i.License.URL}
+	}
+
+	return res
 }
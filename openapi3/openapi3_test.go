@@ -0,0 +1,322 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/internal/tests"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReflector(t *testing.T) {
+	type want struct {
+		schemaJSON  string
+		schemasJSON string
+	}
+	tests := []struct {
+		name   string
+		val    any
+		strict bool
+		want   want
+	}{
+		{
+			name: "nullable",
+			val:  new(tests.TestNullableResponse),
+			want: want{
+				schemaJSON: `{"$ref": "#/components/schemas/tests.TestNullableResponse"}`,
+				schemasJSON: `{
+					"tests.TestNullableResponse": {
+						"type": "object",
+						"properties": {
+							"id": {"type": "integer", "example": 1},
+							"name": {"type": "string", "nullable": true, "example": "foo"},
+							"version": {"type": "string", "enum": ["v1"]}
+						}
+					}
+				}`,
+			},
+		},
+		{
+			name: "one of",
+			val:  new(tests.TestPet),
+			want: want{
+				schemaJSON: `{"$ref": "#/components/schemas/tests.TestPet"}`,
+				schemasJSON: `{
+					"tests.TestCat": {
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string"}, "lives": {"type": "integer"}}
+					},
+					"tests.TestDog": {
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string"}, "breed": {"type": "string"}}
+					},
+					"tests.TestPet": {
+						"oneOf": [{"$ref": "#/components/schemas/tests.TestCat"}, {"$ref": "#/components/schemas/tests.TestDog"}]
+					}
+				}`,
+			},
+		},
+		{
+			name: "any of",
+			val:  new([]tests.TestSearchResult),
+			want: want{
+				schemaJSON: `{"type": "array", "items": {"$ref": "#/components/schemas/tests.TestSearchResult"}}`,
+				schemasJSON: `{
+					"tests.TestCat": {
+						"type": "object",
+						"required": ["name"],
+						"properties": {"name": {"type": "string"}, "lives": {"type": "integer"}}
+					},
+					"tests.TestEvent": {
+						"type": "object",
+						"properties": {"id": {"type": "integer"}, "message": {"type": "string"}}
+					},
+					"tests.TestSearchResult": {
+						"anyOf": [{"$ref": "#/components/schemas/tests.TestCat"}, {"$ref": "#/components/schemas/tests.TestEvent"}]
+					}
+				}`,
+			},
+		},
+		{
+			name:   "strict",
+			val:    new(tests.TestRequest),
+			strict: true,
+			want: want{
+				schemaJSON: `{"$ref": "#/components/schemas/tests.TestRequestStrict"}`,
+				schemasJSON: `{
+					"tests.TestInnerResponseStrict": {
+						"type": "object",
+						"additionalProperties": false,
+						"properties": {"bar_bar": {"type": "integer"}, "foo_foo": {"type": "integer"}}
+					},
+					"tests.TestRequestStrict": {
+						"type": "object",
+						"additionalProperties": false,
+						"properties": {
+							"barb": {"type": "string"},
+							"foob": {"type": "string"},
+							"test_inner_responseb": {"$ref": "#/components/schemas/tests.TestInnerResponseStrict"}
+						}
+					}
+				}`,
+			},
+		},
+		{
+			name: "problem",
+			val:  new(chai.Problem),
+			want: want{
+				schemaJSON: `{"$ref": "#/components/schemas/chai.Problem"}`,
+				schemasJSON: `{
+					"chai.Problem": {
+						"type": "object",
+						"additionalProperties": true,
+						"properties": {
+							"type": {"type": "string", "description": "A URI reference that identifies the problem type", "example": "about:blank"},
+							"title": {"type": "string", "description": "A short, human-readable summary of the problem type", "example": "Not Found"},
+							"status": {"type": "integer", "format": "int64", "description": "The HTTP status code", "example": 404},
+							"detail": {"type": "string", "description": "A human-readable explanation specific to this occurrence of the problem"},
+							"instance": {"type": "string", "description": "A URI reference that identifies the specific occurrence of the problem"}
+						}
+					}
+				}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemas := openapi3.Schemas{}
+
+			r := NewReflector(schemas)
+			if tt.strict {
+				r = r.Strict()
+			}
+
+			schema, err := r.Schema(tt.val)
+			require.NoError(t, err)
+
+			require.JSONEq(t, tt.want.schemaJSON, js(schema))
+			require.JSONEq(t, tt.want.schemasJSON, js(schemas))
+		})
+	}
+}
+
+func TestDocs(t *testing.T) {
+	type args struct {
+		routes []*Route
+		opts   []openapi2.Option
+	}
+	tests := []struct {
+		name     string
+		args     args
+		filePath string
+		wantErr  bool
+	}{
+		{
+			name: "t1",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test1/{id}",
+						Params: []spec.Parameter{
+							{ParamProps: spec.ParamProps{Name: "id", In: "path", Required: true}, CommonValidations: spec.CommonValidations{Pattern: "^[0-9]+$"}},
+						},
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (*tests.TestNullableResponse, int, error) {
+							return nil, 0, nil
+						}, chai.WithCodecs(chai.JSON, chai.XML), chai.WithErrors(testErrors())),
+					},
+				},
+				opts: []openapi2.Option{openapi2.WithReflection()},
+			},
+			filePath: "testdata/t1.json",
+		},
+		{
+			name: "t2",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "GET",
+						Path:   "/test2/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestParamsOnlyRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						},
+							chai.WithErrorWriter(chai.ProblemErrorWriter{}),
+							chai.WithErrors(testErrors()),
+							chai.WithResponseHeader(0, "ETag", "The version of the response"),
+							chai.WithLink(0, "self", "get-test2", map[string]string{"id": "$request.path.id"}),
+						),
+						Name: "get-test2",
+					},
+				},
+				opts: []openapi2.Option{openapi2.WithReflection()},
+			},
+			filePath: "testdata/t2.json",
+		},
+		{
+			name: "t3",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test3/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestAdoptRequest, w http.ResponseWriter, r *http.Request) ([]tests.TestPet, int, error) {
+							return nil, 0, nil
+						}, chai.WithStrictDecoding()),
+					},
+					{
+						Method: "PUT",
+						Path:   "/test3/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestAdoptRequest, w http.ResponseWriter, r *http.Request) (*tests.TestPet, int, error) {
+							return nil, 0, nil
+						}),
+					},
+				},
+				opts: []openapi2.Option{openapi2.WithReflection()},
+			},
+			filePath: "testdata/t3.json",
+		},
+		{
+			name: "t4",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test4",
+
+						// @Summary      Create a test
+						// @Tags         tests
+						// @Param        X-Request-Id  header  string  false  "The ID of the request"
+						// @Success      201  {object}  tests.TestStruct  "Created"
+						// @Failure      404  "Not Found"
+						Handler: chai.NewReqResHandler(func(req *tests.TestRequest, w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+							return nil, 0, nil
+						}),
+						Middlewares: []func(http.Handler) http.Handler{testAuth},
+					},
+					{
+						Method: "PUT",
+						Path:   "/test4/{id}",
+
+						// @Summary      Adopt a pet
+						Handler: chai.NewReqResHandler(func(req *tests.TestAdoptRequest, w http.ResponseWriter, r *http.Request) (*tests.TestPet, int, error) {
+							return nil, 0, nil
+						}),
+					},
+				},
+			},
+			filePath: "testdata/t4.json",
+		},
+		{
+			name: "t5",
+			args: args{
+				routes: []*Route{
+					{
+						Method: "POST",
+						Path:   "/test5/{id}",
+						Handler: chai.NewReqResHandler(func(req *tests.TestUploadRequest, w http.ResponseWriter, r *http.Request) (*tests.TestEvent, int, error) {
+							return nil, 0, nil
+						}, chai.WithMultipart()),
+					},
+					{
+						Method: "GET",
+						Path:   "/test5/{topic}/ws",
+						Handler: chai.NewWebSocketHandler(func(req *tests.TestEventsRequest, conn *chai.WebSocketConn[*tests.TestRequest, *tests.TestEvent], r *http.Request) error {
+							return nil
+						}, chai.WithErrorWriter(chai.ProblemErrorWriter{})),
+					},
+				},
+				opts: []openapi2.Option{openapi2.WithReflection()},
+			},
+			filePath: "testdata/t5.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Docs(tt.args.routes, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Docs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			require.JSONEq(t, load(t, tt.filePath), js(got))
+		})
+	}
+}
+
+var testAuth = chai.DocumentMiddleware(func(next http.Handler) http.Handler {
+	return next
+}, chai.MiddlewareDocs{
+	Headers:   []chai.MiddlewareHeader{{Name: "X-Api-Key", Description: "The API key", Required: true}},
+	Security:  []map[string][]string{{"ApiKeyAuth": {}}},
+	Responses: map[int]string{http.StatusUnauthorized: "Unauthorized"},
+})
+
+func testErrors() *chai.ErrorRegistry {
+	er := chai.NewErrorRegistry().Register(errors.New("not found"), http.StatusNotFound, "")
+
+	return chai.RegisterErrorType[*tests.TestErrorPtr](er, http.StatusConflict, "the account is locked")
+}
+
+func load(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func js(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(b)
+}
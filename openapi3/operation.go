@@ -0,0 +1,787 @@
+package openapi3
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/internal/docgen"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/swag"
+	"github.com/go-openapi/spec"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// generator builds the OpenAPI 3 operation of a route. The metadata of the operation, and the parameters, bodies and responses
+// declared with swaggo annotations, are taken from the annotations, and the rest is built from the types of the handler.
+type generator struct {
+	parser    *swag.Parser
+	hd        *openapi2.HandlerDocs
+	route     *Route
+	h         http.Handler
+	reflector *Reflector
+	opts      []openapi2.Option
+}
+
+func (g *generator) operation() (*openapi3.Operation, error) {
+	op2 := &g.hd.Operation.Operation
+
+	op := openapi3.NewOperation()
+	op.OperationID = op2.ID
+	op.Summary = op2.Summary
+	op.Description = op2.Description
+	op.Tags = op2.Tags
+	op.Deprecated = op2.Deprecated
+
+	if op.OperationID == "" {
+		op.OperationID = g.route.Name
+	}
+
+	if op2.ExternalDocs != nil {
+		op.ExternalDocs = &openapi3.ExternalDocs{Description: op2.ExternalDocs.Description, URL: op2.ExternalDocs.URL}
+	}
+
+	security := op2.Security
+	mwDocs := docgen.MiddlewareDocs(g.route.Middlewares)
+
+	var err error
+
+	op.Parameters, err = g.parameters(op2.Parameters, mwDocs.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	op.RequestBody, err = g.requestBody(op2)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := g.responses(op2)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sr := range mwDocs.Security {
+		if !docgen.ContainsSecurity(security, sr) {
+			security = append(security, sr)
+		}
+	}
+
+	for _, tag := range mwDocs.Tags {
+		if !contains(op.Tags, tag) {
+			op.Tags = append(op.Tags, tag)
+		}
+	}
+
+	for code, description := range mwDocs.Responses {
+		if _, ok := responses[code]; !ok {
+			responses[code] = openapi3.NewResponse().WithDescription(description)
+		}
+	}
+
+	if len(security) > 0 {
+		srs := openapi3.SecurityRequirements{}
+		for _, sr := range security {
+			srs = append(srs, openapi3.SecurityRequirement(sr))
+		}
+		op.Security = &srs
+	}
+
+	op.Responses = g.links(responses)
+
+	op.Callbacks, err = g.callbacks()
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// schema returns the schema of the type v points to, and adds the components it refers to. The definitions that swag
+// parses from the Go source, unless the schemas are built by reflection, describe the components, see addDescriptions.
+func (g *generator) schema(v any, strict bool) (*openapi3.SchemaRef, error) {
+	if _, err := g.hd.Schema(v); err != nil {
+		return nil, err
+	}
+
+	if strict {
+		return g.reflector.Strict().Schema(v)
+	}
+
+	return g.reflector.Schema(v)
+}
+
+// parameters returns the parameters of the operation that are not part of the request body, sorted by location and name.
+// The parameters declared with annotations override those of the request type, which override those of the route,
+// which override the headers of the documented middlewares.
+func (g *generator) parameters(annotated []spec.Parameter, headers []chai.MiddlewareHeader) (openapi3.Parameters, error) {
+	params := map[pk]*openapi3.Parameter{}
+
+	for _, h := range headers {
+		params[pk{chai.InHeader, h.Name}] = openapi3.NewHeaderParameter(h.Name).
+			WithDescription(h.Description).
+			WithRequired(h.Required).
+			WithSchema(openapi3.NewStringSchema())
+	}
+
+	for _, p := range g.route.Params {
+		params[pk{p.In, p.Name}] = fromV2Param(p)
+	}
+
+	if reqer, ok := g.h.(chai.Reqer); ok {
+		for _, pf := range chai.ParamFields(reflect.TypeOf(reqer.Req())) {
+			p, err := fieldParam(pf, g.route.Params)
+			if err != nil {
+				return nil, err
+			}
+
+			params[pk{p.In, p.Name}] = p
+		}
+	}
+
+	for _, p := range annotated {
+		if p.In == "body" || p.In == "formData" {
+			continue
+		}
+
+		params[pk{p.In, p.Name}] = fromV2Param(p)
+	}
+
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]pk, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].in == keys[j].in {
+			return keys[i].name < keys[j].name
+		}
+
+		return keys[i].in < keys[j].in
+	})
+
+	res := openapi3.NewParameters()
+	for _, k := range keys {
+		res = append(res, &openapi3.ParameterRef{Value: params[k]})
+	}
+
+	return res, nil
+}
+
+type pk struct {
+	in   string
+	name string
+}
+
+// fieldParam returns the parameter declared with a struct tag on the request type. The regex pattern, the enum and the required flag
+// of the route's parameter of the same name apply to it, unless its field declares its own.
+func fieldParam(pf chai.ParamField, routeParams []spec.Parameter) (*openapi3.Parameter, error) {
+	schema := paramTypeSchema(pf.Field.Type)
+
+	if format := pf.Field.Tag.Get("format"); format != "" {
+		schema.Format = format
+	}
+
+	rules, err := chai.FieldRules(pf.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaRules(schema, rules)
+
+	p := &openapi3.Parameter{
+		Name:        pf.Name,
+		In:          pf.In,
+		Description: pf.Field.Tag.Get("description"),
+		Required:    pf.In == chai.InPath || rules.Required,
+		Schema:      openapi3.NewSchemaRef("", schema),
+	}
+
+	for _, rp := range routeParams {
+		if rp.In != p.In || rp.Name != p.Name {
+			continue
+		}
+
+		if schema.Pattern == "" {
+			schema.Pattern = rp.Pattern
+		}
+
+		// The router only matches the requests that have the parameter, e.g. for the query matchers of gorilla/mux.
+		if rp.Required {
+			p.Required = true
+		}
+
+		if len(schema.Enum) == 0 {
+			schema.Enum = rp.Enum
+		}
+	}
+
+	return p, nil
+}
+
+// paramTypeSchema returns the schema of the values of a parameter or a form field of type t, which are decoded from strings.
+func paramTypeSchema(t reflect.Type) *openapi3.Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return openapi3.NewDateTimeSchema()
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return openapi3.NewStringSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return openapi3.NewBoolSchema()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return openapi3.NewIntegerSchema()
+	case reflect.Int32, reflect.Uint32:
+		return openapi3.NewInt32Schema()
+	case reflect.Int64, reflect.Uint64:
+		return openapi3.NewInt64Schema()
+	case reflect.Float32:
+		return &openapi3.Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return openapi3.NewFloat64Schema().WithFormat("double")
+	case reflect.Slice, reflect.Array:
+		return openapi3.NewArraySchema().WithItems(paramTypeSchema(t.Elem()))
+	default:
+		return openapi3.NewStringSchema()
+	}
+}
+
+// fromV2Param converts a parameter of the annotations or of the route. The csv query parameters of the annotations
+// are form parameters that are not exploded.
+func fromV2Param(p spec.Parameter) *openapi3.Parameter {
+	param := &openapi3.Parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.Required,
+		Schema:      fromV2Schema(paramSchema(p)),
+	}
+
+	if p.In == "query" && p.CollectionFormat == "csv" {
+		param.Style = openapi3.SerializationForm
+		param.Explode = openapi3.BoolPtr(false)
+	}
+
+	return param
+}
+
+// requestBody returns the body of the operation's requests, with a media type for each type it consumes. The body declared with
+// annotations, if any, is documented with its own schema, or with that of the request type if it has none.
+// Form data parameters and multipart request types are documented as the properties of a multipart/form-data body.
+func (g *generator) requestBody(op2 *spec.Operation) (*openapi3.RequestBodyRef, error) {
+	reqer, hasReq := g.h.(chai.Reqer)
+	decodes := hasReq && openapi2.DecodesBody(g.h) && chai.HasBody(reflect.TypeOf(reqer.Req()))
+
+	consumes := op2.Consumes
+	if len(consumes) == 0 && decodes {
+		consumes = openapi2.RequestContentTypes(g.h)
+	}
+
+	for _, p := range op2.Parameters {
+		if p.In != "body" {
+			continue
+		}
+
+		schema := fromV2Schema(p.Schema)
+		if schema == nil && hasReq {
+			var err error
+			schema, err = g.schema(reqer.Req(), openapi2.DecodesStrictly(g.h))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		body := openapi3.NewRequestBody().
+			WithDescription(p.Description).
+			WithRequired(p.Required).
+			WithContent(openapi3.NewContentWithSchemaRef(schema, withDefault(consumes, "application/json")))
+
+		return &openapi3.RequestBodyRef{Value: body}, nil
+	}
+
+	multipart := decodes && contains(openapi2.RequestContentTypes(g.h), chai.MultipartContentType)
+
+	if decodes && !multipart {
+		schema, err := g.schema(reqer.Req(), openapi2.DecodesStrictly(g.h))
+		if err != nil {
+			return nil, err
+		}
+
+		body := openapi3.NewRequestBody().WithContent(openapi3.NewContentWithSchemaRef(schema, consumes))
+
+		return &openapi3.RequestBodyRef{Value: body}, nil
+	}
+
+	var fields []chai.MultipartField
+	if multipart {
+		var err error
+		fields, err = chai.MultipartFields(reflect.TypeOf(reqer.Req()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := formSchema(fields, op2.Parameters)
+	if err != nil || schema == nil {
+		return nil, err
+	}
+
+	body := openapi3.NewRequestBody().
+		WithRequired(len(schema.Required) > 0).
+		WithContent(openapi3.NewContentWithSchema(schema, withDefault(formContentTypes(consumes), chai.MultipartContentType)))
+
+	return &openapi3.RequestBodyRef{Value: body}, nil
+}
+
+// formSchema returns the object schema of the fields of a multipart request type and of the form data parameters, which override them,
+// or nil if there are none. Files are binary strings, and arrays of them for the fields that hold several files.
+func formSchema(fields []chai.MultipartField, params []spec.Parameter) (*openapi3.Schema, error) {
+	props := openapi3.Schemas{}
+	required := map[string]bool{}
+
+	for _, mf := range fields {
+		var prop *openapi3.Schema
+
+		rules, err := chai.FieldRules(mf.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		switch mf.Kind {
+		case chai.MultipartValue:
+			prop = paramTypeSchema(mf.Field.Type)
+			if format := mf.Field.Tag.Get("format"); format != "" {
+				prop.Format = format
+			}
+			schemaRules(prop, rules)
+		case chai.MultipartFiles, chai.MultipartParts:
+			prop = openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema().WithFormat("binary"))
+		default:
+			prop = openapi3.NewStringSchema().WithFormat("binary")
+		}
+
+		prop.Description = mf.Field.Tag.Get("description")
+		props[mf.Name] = openapi3.NewSchemaRef("", prop)
+		required[mf.Name] = rules.Required
+	}
+
+	for _, p := range params {
+		if p.In != "formData" {
+			continue
+		}
+
+		prop := fromV2Schema(paramSchema(p)).Value
+		if p.Type == "file" {
+			prop = openapi3.NewStringSchema().WithFormat("binary")
+		}
+
+		prop.Description = p.Description
+		props[p.Name] = openapi3.NewSchemaRef("", prop)
+		required[p.Name] = p.Required
+	}
+
+	if len(props) == 0 {
+		return nil, nil
+	}
+
+	res := openapi3.NewObjectSchema()
+	res.Properties = props
+
+	for name := range props {
+		if required[name] {
+			res.Required = append(res.Required, name)
+		}
+	}
+	sort.Strings(res.Required)
+
+	return res, nil
+}
+
+func formContentTypes(consumes []string) []string {
+	res := make([]string, 0)
+
+	for _, ct := range consumes {
+		if ct == chai.MultipartContentType || ct == "application/x-www-form-urlencoded" {
+			res = append(res, ct)
+		}
+	}
+
+	return res
+}
+
+// responses returns the responses of the operation by status code, 0 for the default response. The successful responses have a media type
+// for each type the handler produces, and the error responses the media type of its error writer, see chai.ErrorContentTyper.
+// The responses declared with annotations that have no schema have that of the handler's response or error type.
+func (g *generator) responses(op2 *spec.Operation) (map[int]*openapi3.Response, error) {
+	if _, ok := g.h.(chai.WebSocketer); ok {
+		return g.webSocketResponses(op2)
+	}
+
+	res := map[int]*openapi3.Response{}
+
+	resErrer, ok := g.h.(chai.ResErrer)
+	if !ok {
+		g.annotatedResponses(res, op2, withDefault(op2.Produces, "application/json"), nil, nil)
+
+		return res, nil
+	}
+
+	resType := chai.BodyType(reflect.TypeOf(resErrer.Res()))
+	binary := chai.IsBinary(chai.BodyType(reflect.TypeOf(resErrer.Res()).Elem()))
+
+	produces := op2.Produces
+	if len(produces) == 0 {
+		produces = openapi2.ProducedTypes(g.h, binary)
+	}
+
+	resSchema := openapi3.NewSchemaRef("", openapi3.NewStringSchema().WithFormat("binary"))
+	if !binary {
+		var err error
+		resSchema, err = g.schema(reflect.New(resType).Interface(), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	errSchema, err := g.errorSchema(resErrer.Err())
+	if err != nil {
+		return nil, err
+	}
+
+	g.annotatedResponses(res, op2, produces, resSchema, errSchema)
+
+	if len(successCodes(res)) == 0 {
+		res[http.StatusOK] = openapi3.NewResponse().WithDescription("").WithContent(openapi3.NewContentWithSchemaRef(resSchema, produces))
+	}
+
+	if !hasErrors(res) {
+		res[0] = g.errorResponse("", errSchema)
+	}
+
+	for _, m := range openapi2.ErrorMappings(g.h, resErrer.Err()) {
+		if _, ok := res[m.Code]; !ok {
+			res[m.Code] = g.errorResponse(m.Message, errSchema)
+		}
+	}
+
+	if rh, ok := g.h.(chai.ResponseHeaderer); ok {
+		for _, header := range rh.ResponseHeaders() {
+			codes := []int{header.Code}
+			if header.Code == 0 {
+				codes = successCodes(res)
+			}
+
+			for _, code := range codes {
+				r, ok := res[code]
+				if !ok {
+					if code >= http.StatusBadRequest {
+						continue
+					}
+
+					r = openapi3.NewResponse().WithDescription("").WithContent(openapi3.NewContentWithSchemaRef(resSchema, produces))
+					res[code] = r
+				}
+
+				if r.Headers == nil {
+					r.Headers = openapi3.Headers{}
+				}
+
+				r.Headers[header.Name] = &openapi3.HeaderRef{
+					Value: &openapi3.Header{
+						Parameter: openapi3.Parameter{
+							Description: header.Description,
+							Schema:      openapi3.NewSchemaRef("", &openapi3.Schema{Type: header.Type}),
+						},
+					},
+				}
+			}
+		}
+	}
+
+	if description := openapi2.StreamDescription(g.h); description != "" {
+		for _, code := range successCodes(res) {
+			if *res[code].Description == "" {
+				res[code].Description = &description
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// webSocketResponses returns the responses of the handshake of WebSocket handlers: the 101 response that upgrades the connection,
+// and the errors that are written before the upgrade. The messages exchanged on the connection are not part of the OpenAPI document.
+func (g *generator) webSocketResponses(op2 *spec.Operation) (map[int]*openapi3.Response, error) {
+	res := map[int]*openapi3.Response{}
+
+	errer, ok := g.h.(interface{ Err() any })
+	if !ok {
+		return res, nil
+	}
+
+	errSchema, err := g.errorSchema(errer.Err())
+	if err != nil {
+		return nil, err
+	}
+
+	g.annotatedResponses(res, op2, withDefault(op2.Produces, "application/json"), nil, errSchema)
+
+	if _, ok := res[http.StatusSwitchingProtocols]; !ok {
+		res[http.StatusSwitchingProtocols] = openapi3.NewResponse().WithDescription("The connection is upgraded to a WebSocket")
+	}
+
+	if _, ok := res[http.StatusUpgradeRequired]; !ok {
+		res[http.StatusUpgradeRequired] = g.errorResponse("The request is not a WebSocket handshake", errSchema)
+	}
+
+	for _, m := range openapi2.ErrorMappings(g.h, errer.Err()) {
+		if _, ok := res[m.Code]; !ok {
+			res[m.Code] = g.errorResponse(m.Message, errSchema)
+		}
+	}
+
+	res[0] = g.errorResponse("", errSchema)
+
+	return res, nil
+}
+
+// annotatedResponses adds the responses declared with annotations to res. Those that have no schema have resSchema if they are successful,
+// and errSchema otherwise.
+func (g *generator) annotatedResponses(res map[int]*openapi3.Response, op2 *spec.Operation, produces []string, resSchema, errSchema *openapi3.SchemaRef) {
+	if op2.Responses == nil {
+		return
+	}
+
+	responses := make(map[int]spec.Response, len(op2.Responses.StatusCodeResponses)+1)
+	for code, r := range op2.Responses.StatusCodeResponses {
+		responses[code] = r
+	}
+	if op2.Responses.Default != nil {
+		responses[0] = *op2.Responses.Default
+	}
+
+	for code, r := range responses {
+		schema := fromV2Schema(r.Schema)
+
+		var resp *openapi3.Response
+		if isSuccess(code) {
+			if schema == nil {
+				schema = resSchema
+			}
+
+			resp = openapi3.NewResponse().WithDescription(r.Description)
+			if schema != nil {
+				resp.Content = openapi3.NewContentWithSchemaRef(schema, produces)
+			}
+		} else {
+			if schema == nil {
+				schema = errSchema
+			}
+
+			resp = g.errorResponse(r.Description, schema)
+		}
+
+		for name, header := range r.Headers {
+			if resp.Headers == nil {
+				resp.Headers = openapi3.Headers{}
+			}
+
+			resp.Headers[name] = &openapi3.HeaderRef{
+				Value: &openapi3.Header{
+					Parameter: openapi3.Parameter{
+						Description: header.Description,
+						Schema:      openapi3.NewSchemaRef("", &openapi3.Schema{Type: header.Type, Format: header.Format}),
+					},
+				},
+			}
+		}
+
+		res[code] = resp
+	}
+}
+
+// errorSchema returns the schema of the error responses, which is the handler's Err type
+// unless its error writer wraps every error in a type of its own.
+func (g *generator) errorSchema(errType any) (*openapi3.SchemaRef, error) {
+	if ewer, ok := g.h.(chai.ErrorWriterer); ok {
+		if et, ok := ewer.ErrorWriter().(chai.ErrorTyper); ok {
+			errType = et.ErrorType()
+		}
+	}
+
+	if _, ok := errType.(*chai.Problem); ok {
+		return g.reflector.Schema(errType)
+	}
+
+	return g.schema(errType, false)
+}
+
+// errorResponse returns an error response with the schema, whose media type is the one the handler's error writer writes.
+func (g *generator) errorResponse(description string, schema *openapi3.SchemaRef) *openapi3.Response {
+	res := openapi3.NewResponse().WithDescription(description)
+	if schema != nil {
+		res.Content = openapi3.NewContentWithSchemaRef(schema, []string{errorContentType(g.h)})
+	}
+
+	return res
+}
+
+// errorContentType returns the media type of the error responses of the handler, see chai.ErrorContentTyper.
+func errorContentType(h http.Handler) string {
+	if ewer, ok := h.(chai.ErrorWriterer); ok {
+		if ect, ok := ewer.ErrorWriter().(chai.ErrorContentTyper); ok {
+			return ect.ErrorContentType()
+		}
+	}
+
+	return "application/json"
+}
+
+// links returns the responses with the links declared with chai.WithLink.
+func (g *generator) links(responses map[int]*openapi3.Response) openapi3.Responses {
+	res := openapi3.Responses{}
+
+	for code, r := range responses {
+		if lr, ok := g.h.(chai.Linker); ok {
+			for _, link := range lr.Links() {
+				// The links of code 0 are those of the successful responses, which the default response is not.
+				if link.Code == 0 && !isSuccess(code) || link.Code != 0 && link.Code != code {
+					continue
+				}
+
+				if r.Links == nil {
+					r.Links = openapi3.Links{}
+				}
+
+				params := make(map[string]interface{}, len(link.Parameters))
+				for name, expr := range link.Parameters {
+					params[name] = expr
+				}
+
+				r.Links[link.Name] = &openapi3.LinkRef{Value: &openapi3.Link{OperationID: link.OperationID, Parameters: params}}
+			}
+		}
+
+		key := "default"
+		if code != 0 {
+			key = strconv.Itoa(code)
+		}
+
+		res[key] = &openapi3.ResponseRef{Value: r}
+	}
+
+	return res
+}
+
+func successCodes(responses map[int]*openapi3.Response) []int {
+	res := make([]int, 0)
+
+	for code := range responses {
+		if isSuccess(code) {
+			res = append(res, code)
+		}
+	}
+
+	sort.Ints(res)
+
+	return res
+}
+
+func hasErrors(responses map[int]*openapi3.Response) bool {
+	for code := range responses {
+		if !isSuccess(code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSuccess(code int) bool {
+	return code != 0 && code < http.StatusBadRequest
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paramSchema returns the schema of a non-body parameter.
+func paramSchema(p spec.Parameter) *spec.Schema {
+	s := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:             spec.StringOrArray{p.Type},
+			Format:           p.Format,
+			Default:          p.Default,
+			Pattern:          p.Pattern,
+			Enum:             p.Enum,
+			Minimum:          p.Minimum,
+			Maximum:          p.Maximum,
+			ExclusiveMinimum: p.ExclusiveMinimum,
+			ExclusiveMaximum: p.ExclusiveMaximum,
+			MinLength:        p.MinLength,
+			MaxLength:        p.MaxLength,
+			MinItems:         p.MinItems,
+			MaxItems:         p.MaxItems,
+			UniqueItems:      p.UniqueItems,
+			MultipleOf:       p.MultipleOf,
+		},
+	}
+
+	if p.Items != nil {
+		s.Items = &spec.SchemaOrArray{Schema: itemsSchema(p.Items)}
+	}
+
+	return s
+}
+
+func itemsSchema(items *spec.Items) *spec.Schema {
+	s := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:      spec.StringOrArray{items.Type},
+			Format:    items.Format,
+			Default:   items.Default,
+			Pattern:   items.Pattern,
+			Enum:      items.Enum,
+			Minimum:   items.Minimum,
+			Maximum:   items.Maximum,
+			MinLength: items.MinLength,
+			MaxLength: items.MaxLength,
+		},
+	}
+
+	if items.Items != nil {
+		s.Items = &spec.SchemaOrArray{Schema: itemsSchema(items.Items)}
+	}
+
+	return s
+}
+
+func withDefault(ss []string, s string) []string {
+	if len(ss) == 0 {
+		return []string{s}
+	}
+
+	return ss
+}
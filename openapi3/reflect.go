@@ -0,0 +1,398 @@
+package openapi3
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/internal/docgen"
+	"github.com/go-chai/chai/openapi2"
+)
+
+const schemasPrefix = "#/components/schemas/"
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	problemType       = reflect.TypeOf(chai.Problem{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	oneOferType       = reflect.TypeOf((*chai.OneOfer)(nil)).Elem()
+	anyOferType       = reflect.TypeOf((*chai.AnyOfer)(nil)).Elem()
+)
+
+// Reflector builds the OpenAPI 3 schemas of Go types by reflection. The named types are added to the component schemas
+// under the names swag gives them, see openapi2.DefinitionName, and referred to by name.
+//
+// The structs are described like openapi2.Reflector describes them, with the `x-nullable` extension tag as the nullable property.
+// The types that implement chai.OneOfer or chai.AnyOfer are described with a oneOf or an anyOf of the schemas of their variants,
+// and chai.Problem with the members of RFC 7807 problem details.
+type Reflector struct {
+	schemas openapi3.Schemas
+	strict  bool
+}
+
+func NewReflector(schemas openapi3.Schemas) *Reflector {
+	return &Reflector{schemas: schemas}
+}
+
+// Strict returns a reflector that adds the structs to the component schemas with the "Strict" suffix and without additional properties,
+// for the request bodies that are decoded with chai.WithStrictDecoding.
+func (r *Reflector) Strict() *Reflector {
+	return &Reflector{schemas: r.schemas, strict: true}
+}
+
+// Schema returns the schema of the type v points to.
+func (r *Reflector) Schema(v any) (*openapi3.SchemaRef, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return openapi3.NewSchemaRef("", openapi3.NewSchema()), nil
+	}
+
+	return r.TypeSchema(t)
+}
+
+// TypeSchema returns the schema of t, or of the type t points to. It fails if the validation rules of a struct field do not parse.
+func (r *Reflector) TypeSchema(t reflect.Type) (*openapi3.SchemaRef, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == problemType:
+		return r.component(t, func() (*openapi3.Schema, error) {
+			return problemSchema(), nil
+		})
+	case reflect.PointerTo(t).Implements(oneOferType):
+		return r.variantsSchema(t, reflect.New(t).Interface().(chai.OneOfer).OneOf(), func(s *openapi3.Schema, refs openapi3.SchemaRefs) {
+			s.OneOf = refs
+		})
+	case reflect.PointerTo(t).Implements(anyOferType):
+		return r.variantsSchema(t, reflect.New(t).Interface().(chai.AnyOfer).AnyOf(), func(s *openapi3.Schema, refs openapi3.SchemaRefs) {
+			s.AnyOf = refs
+		})
+	case t == timeType:
+		return inline(openapi3.NewDateTimeSchema()), nil
+	case t == durationType:
+		return inline(openapi3.NewInt64Schema()), nil
+	case t == rawMessageType:
+		return inline(openapi3.NewSchema()), nil
+	case t == errorType:
+		return inline(openapi3.NewStringSchema()), nil
+	case t.Kind() != reflect.Struct && reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return inline(openapi3.NewSchema()), nil
+	case reflect.PointerTo(t).Implements(textMarshalerType):
+		return inline(openapi3.NewStringSchema()), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return inline(openapi3.NewBoolSchema()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return inline(openapi3.NewIntegerSchema()), nil
+	case reflect.Int32, reflect.Uint32:
+		return inline(openapi3.NewInt32Schema()), nil
+	case reflect.Int64, reflect.Uint64:
+		return inline(openapi3.NewInt64Schema()), nil
+	case reflect.Float32:
+		return inline(&openapi3.Schema{Type: "number", Format: "float"}), nil
+	case reflect.Float64:
+		return inline(openapi3.NewFloat64Schema().WithFormat("double")), nil
+	case reflect.String:
+		return inline(openapi3.NewStringSchema()), nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return inline(openapi3.NewBytesSchema()), nil
+		}
+
+		items, err := r.TypeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		s := openapi3.NewArraySchema()
+		s.Items = items
+
+		return inline(s), nil
+	case reflect.Map:
+		values, err := r.TypeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		s := openapi3.NewObjectSchema()
+		s.AdditionalProperties = values
+
+		return inline(s), nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			s, err := r.structSchema(t)
+			if err != nil {
+				return nil, err
+			}
+
+			return inline(s), nil
+		}
+
+		return r.component(t, func() (*openapi3.Schema, error) {
+			return r.structSchema(t)
+		})
+	default:
+		return inline(openapi3.NewSchema()), nil
+	}
+}
+
+// component adds the schema built by fn to the component schemas under the name of t, unless it is there already,
+// and returns a reference to it.
+func (r *Reflector) component(t reflect.Type, fn func() (*openapi3.Schema, error)) (*openapi3.SchemaRef, error) {
+	name := openapi2.DefinitionName(t)
+	if r.strict && t.Kind() == reflect.Struct && t != problemType {
+		name += "Strict"
+	}
+
+	if _, ok := r.schemas[name]; !ok {
+		// The schema is added before it is built, so that recursive types refer to it.
+		r.schemas[name] = inline(openapi3.NewSchema())
+
+		s, err := fn()
+		if err != nil {
+			delete(r.schemas, name)
+			return nil, err
+		}
+
+		r.schemas[name].Value = s
+	}
+
+	return openapi3.NewSchemaRef(schemasPrefix+name, r.schemas[name].Value), nil
+}
+
+// variantsSchema returns the schema of a type that is one or any of the types of the variants, which set adds to the schema.
+// The variants are decoded by the type itself, so they are never strict.
+func (r *Reflector) variantsSchema(t reflect.Type, variants []any, set func(*openapi3.Schema, openapi3.SchemaRefs)) (*openapi3.SchemaRef, error) {
+	fn := func() (*openapi3.Schema, error) {
+		refs := make(openapi3.SchemaRefs, 0, len(variants))
+
+		for _, v := range variants {
+			ref, err := NewReflector(r.schemas).Schema(v)
+			if err != nil {
+				return nil, err
+			}
+
+			refs = append(refs, ref)
+		}
+
+		s := openapi3.NewSchema()
+		set(s, refs)
+
+		return s, nil
+	}
+
+	if t.Name() == "" {
+		s, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		return inline(s), nil
+	}
+
+	return NewReflector(r.schemas).component(t, fn)
+}
+
+func (r *Reflector) structSchema(t reflect.Type) (*openapi3.Schema, error) {
+	s := openapi3.NewObjectSchema()
+	s.Properties = openapi3.Schemas{}
+
+	if r.strict {
+		s.AdditionalPropertiesAllowed = openapi3.BoolPtr(false)
+	}
+
+	err := r.addFields(s, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// addFields adds the properties of the fields of the struct type t to s.
+func (r *Reflector) addFields(s *openapi3.Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if (name == "-" && opts == "") || docgen.IsParam(f) {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if err := r.addFields(s, ft); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := inline(openapi3.NewStringSchema())
+		if !strings.Contains(opts, "string") {
+			var err error
+			prop, err = r.TypeSchema(f.Type)
+			if err != nil {
+				return err
+			}
+		}
+
+		rules, err := chai.FieldRules(f)
+		if err != nil {
+			return err
+		}
+
+		if rules.Required {
+			s.Required = append(s.Required, name)
+		}
+
+		if prop.Ref == "" {
+			fieldTags(prop.Value, f)
+			schemaRules(prop.Value, rules)
+		}
+
+		s.Properties[name] = prop
+	}
+
+	return nil
+}
+
+// fieldTags applies the swag tags of f to its schema.
+func fieldTags(s *openapi3.Schema, f reflect.StructField) {
+	if d := f.Tag.Get("description"); d != "" {
+		s.Description = d
+	}
+
+	if format := f.Tag.Get("format"); format != "" {
+		s.Format = format
+	}
+
+	itemType := s.Type
+	if s.Type == "array" && s.Items != nil && s.Items.Value != nil {
+		itemType = s.Items.Value.Type
+	}
+
+	if example, ok := docgen.Example(f, s.Type, itemType); ok {
+		s.Example = example
+	}
+
+	if def, ok := docgen.Default(f, s.Type); ok {
+		s.Default = def
+	}
+
+	for _, ext := range docgen.Extensions(f) {
+		// OpenAPI 3 schemas are nullable themselves.
+		if ext.Name == "x-nullable" {
+			s.Nullable = ext.Value == true || ext.Value == "true"
+			continue
+		}
+
+		if s.Extensions == nil {
+			s.Extensions = map[string]interface{}{}
+		}
+		s.Extensions[ext.Name] = ext.Value
+	}
+}
+
+// schemaRules applies the validation rules of a field to its schema.
+func schemaRules(s *openapi3.Schema, rules *chai.Rules) {
+	switch s.Type {
+	case "string":
+		s.MinLength, s.MaxLength = toUint64(rules.Min), toUint64Ptr(rules.Max)
+	case "array":
+		s.MinItems, s.MaxItems = toUint64(rules.Min), toUint64Ptr(rules.Max)
+	case "integer", "number":
+		s.Min, s.Max = rules.Min, rules.Max
+	}
+
+	if rules.Len != nil {
+		n := uint64(*rules.Len)
+		if s.Type == "array" {
+			s.MinItems, s.MaxItems = n, &n
+		} else {
+			s.MinLength, s.MaxLength = n, &n
+		}
+	}
+
+	// The values of arrays are those of their items.
+	enumSchema := s
+	if s.Type == "array" && s.Items != nil && s.Items.Value != nil && s.Items.Ref == "" {
+		enumSchema = s.Items.Value
+	}
+
+	enumSchema.Enum = append(enumSchema.Enum, docgen.Enum(rules, enumSchema.Type)...)
+
+	if rules.Format != "" && s.Format == "" {
+		s.Format = rules.Format
+	}
+
+	if rules.Pattern != nil && s.Pattern == "" {
+		s.Pattern = rules.Pattern.String()
+	}
+}
+
+// problemSchema returns the schema of RFC 7807 problem details, whose extension members are additional properties.
+func problemSchema() *openapi3.Schema {
+	s := openapi3.NewObjectSchema().
+		WithProperty("type", withExample(openapi3.NewStringSchema(), "A URI reference that identifies the problem type", "about:blank")).
+		WithProperty("title", withExample(openapi3.NewStringSchema(), "A short, human-readable summary of the problem type", "Not Found")).
+		WithProperty("status", withExample(openapi3.NewInt64Schema(), "The HTTP status code", 404)).
+		WithProperty("detail", withExample(openapi3.NewStringSchema(), "A human-readable explanation specific to this occurrence of the problem", nil)).
+		WithProperty("instance", withExample(openapi3.NewStringSchema(), "A URI reference that identifies the specific occurrence of the problem", nil))
+	s.AdditionalPropertiesAllowed = openapi3.BoolPtr(true)
+
+	return s
+}
+
+func withExample(s *openapi3.Schema, description string, example any) *openapi3.Schema {
+	s.Description = description
+	s.Example = example
+
+	return s
+}
+
+// inline returns a reference-less SchemaRef of s.
+func inline(s *openapi3.Schema) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("", s)
+}
+
+func toUint64(f *float64) uint64 {
+	if f == nil {
+		return 0
+	}
+
+	return uint64(*f)
+}
+
+func toUint64Ptr(f *float64) *uint64 {
+	if f == nil {
+		return nil
+	}
+
+	n := uint64(*f)
+
+	return &n
+}
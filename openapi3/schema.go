@@ -0,0 +1,208 @@
+package openapi3
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-openapi/spec"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+// fromV2Schema converts a Swagger 2 schema of the swaggo annotations, whose references point to the definitions,
+// to an OpenAPI 3 schema, whose references point to the component schemas. Files are binary strings,
+// and the x-nullable extensions, e.g. declared with `extensions:"x-nullable"` struct tags, are the nullable property.
+func fromV2Schema(s *spec.Schema) *openapi3.SchemaRef {
+	if s == nil {
+		return nil
+	}
+
+	if ref := s.Ref.String(); ref != "" {
+		return openapi3.NewSchemaRef(schemasPrefix+strings.TrimPrefix(ref, definitionsPrefix), nil)
+	}
+
+	res := &openapi3.Schema{
+		Title:        s.Title,
+		Format:       s.Format,
+		Description:  s.Description,
+		Enum:         s.Enum,
+		Default:      s.Default,
+		Example:      s.Example,
+		UniqueItems:  s.UniqueItems,
+		ExclusiveMin: s.ExclusiveMinimum,
+		ExclusiveMax: s.ExclusiveMaximum,
+		ReadOnly:     s.ReadOnly,
+		Min:          s.Minimum,
+		Max:          s.Maximum,
+		MultipleOf:   s.MultipleOf,
+		MinLength:    toUint64(toFloat64(s.MinLength)),
+		MaxLength:    toUint64Ptr(toFloat64(s.MaxLength)),
+		Pattern:      s.Pattern,
+		MinItems:     toUint64(toFloat64(s.MinItems)),
+		MaxItems:     toUint64Ptr(toFloat64(s.MaxItems)),
+		Required:     s.Required,
+	}
+
+	if len(s.Type) > 0 {
+		res.Type = s.Type[0]
+	}
+
+	if res.Type == "file" {
+		res.Type, res.Format = "string", "binary"
+	}
+
+	if s.Items != nil {
+		res.Items = fromV2Schema(s.Items.Schema)
+	}
+
+	for _, all := range s.AllOf {
+		all := all
+		res.AllOf = append(res.AllOf, fromV2Schema(&all))
+	}
+
+	if len(s.Properties) > 0 {
+		res.Properties = make(openapi3.Schemas, len(s.Properties))
+		for name, prop := range s.Properties {
+			prop := prop
+			res.Properties[name] = fromV2Schema(&prop)
+		}
+	}
+
+	if ap := s.AdditionalProperties; ap != nil {
+		if ap.Schema != nil {
+			res.AdditionalProperties = fromV2Schema(ap.Schema)
+		} else {
+			res.AdditionalPropertiesAllowed = openapi3.BoolPtr(ap.Allows)
+		}
+	}
+
+	for key, value := range s.Extensions {
+		if key == "x-nullable" {
+			res.Nullable = value == true || value == "true"
+			continue
+		}
+
+		if res.Extensions == nil {
+			res.Extensions = map[string]interface{}{}
+		}
+		res.Extensions[key] = value
+	}
+
+	return openapi3.NewSchemaRef("", res)
+}
+
+// addReferenced adds the definitions the schema refers to, directly or through other definitions, to the component schemas
+// that do not have them yet.
+func addReferenced(schemas openapi3.Schemas, definitions spec.Definitions, ref *openapi3.SchemaRef) {
+	if ref == nil {
+		return
+	}
+
+	if ref.Ref != "" {
+		name := strings.TrimPrefix(ref.Ref, schemasPrefix)
+
+		def, ok := definitions[name]
+		if _, added := schemas[name]; added || !ok {
+			return
+		}
+
+		schemas[name] = fromV2Schema(&def)
+		addReferenced(schemas, definitions, schemas[name])
+
+		return
+	}
+
+	s := ref.Value
+	if s == nil {
+		return
+	}
+
+	for _, p := range s.Properties {
+		addReferenced(schemas, definitions, p)
+	}
+
+	for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, r := range refs {
+			addReferenced(schemas, definitions, r)
+		}
+	}
+
+	addReferenced(schemas, definitions, s.Items)
+	addReferenced(schemas, definitions, s.AdditionalProperties)
+}
+
+// addDescriptions copies the descriptions of the definitions that swag parsed from the Go source onto the component schemas
+// of the same name, and onto their properties, that have none.
+func addDescriptions(schemas openapi3.Schemas, definitions spec.Definitions) {
+	for name, ref := range schemas {
+		def, ok := definitions[name]
+		if !ok || ref.Value == nil {
+			continue
+		}
+
+		if ref.Value.Description == "" {
+			ref.Value.Description = def.Description
+		}
+
+		for name, prop := range ref.Value.Properties {
+			if prop.Ref != "" || prop.Value.Description != "" {
+				continue
+			}
+
+			prop.Value.Description = def.Properties[name].Description
+		}
+	}
+}
+
+func toFloat64(n *int64) *float64 {
+	if n == nil {
+		return nil
+	}
+
+	f := float64(*n)
+
+	return &f
+}
+
+// addDefinitions adds the definitions of the parser's swagger that the annotations of the operations refer to to the component schemas,
+// and describes the component schemas with the definitions of the same name.
+func addDefinitions(docs *openapi3.T, definitions spec.Definitions) {
+	for _, item := range docs.Paths {
+		walkPathItem(item, func(ref *openapi3.SchemaRef) {
+			addReferenced(docs.Components.Schemas, definitions, ref)
+		})
+	}
+
+	addDescriptions(docs.Components.Schemas, definitions)
+}
+
+// walkPathItem calls fn with the schemas of the parameters, the request bodies, the responses and the callbacks of the operations of item.
+func walkPathItem(item *openapi3.PathItem, fn func(*openapi3.SchemaRef)) {
+	for _, op := range item.Operations() {
+		for _, p := range op.Parameters {
+			fn(p.Value.Schema)
+		}
+
+		if op.RequestBody != nil {
+			for _, mt := range op.RequestBody.Value.Content {
+				fn(mt.Schema)
+			}
+		}
+
+		for _, r := range op.Responses {
+			for _, mt := range r.Value.Content {
+				fn(mt.Schema)
+			}
+
+			for _, h := range r.Value.Headers {
+				fn(h.Value.Schema)
+			}
+		}
+
+		for _, cb := range op.Callbacks {
+			for _, item := range *cb.Value {
+				walkPathItem(item, fn)
+			}
+		}
+	}
+}
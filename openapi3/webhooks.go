@@ -0,0 +1,122 @@
+package openapi3
+
+import (
+	"reflect"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/swag"
+)
+
+// AddWebhooks documents the events of wr in the `x-webhooks` extension of docs, which is the `webhooks` section of OpenAPI 3.1
+// documents, and adds the schemas of their payloads to the components of docs.
+func AddWebhooks(docs *openapi3.T, wr *chai.WebhookRegistry, opts ...openapi2.Option) error {
+	items, err := WebhookPathItems(docs, wr.Events(), opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	if docs.Extensions == nil {
+		docs.Extensions = map[string]interface{}{}
+	}
+	docs.Extensions["x-webhooks"] = items
+
+	return nil
+}
+
+// WebhookPathItems returns the path items of the requests the events are sent with, by event name,
+// and adds the schemas of their payloads to the components of docs, see Reflector.
+func WebhookPathItems(docs *openapi3.T, events []chai.WebhookEvent, opts ...openapi2.Option) (map[string]*openapi3.PathItem, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	if docs.Components.Schemas == nil {
+		docs.Components.Schemas = openapi3.Schemas{}
+	}
+
+	parser := openapi2.NewParser()
+	r := NewReflector(docs.Components.Schemas)
+	items := make(map[string]*openapi3.PathItem, len(events))
+
+	for _, ev := range events {
+		item, err := webhookPathItem(parser, r, ev, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		items[ev.Name] = item
+	}
+
+	addDescriptions(docs.Components.Schemas, parser.GetSwagger().Definitions)
+
+	return items, nil
+}
+
+// callbacks returns the callbacks declared with chai.WithCallback on the handler, by event name.
+func (g *generator) callbacks() (openapi3.Callbacks, error) {
+	cber, ok := g.h.(chai.Callbacker)
+	if !ok || len(cber.Callbacks()) == 0 {
+		return nil, nil
+	}
+
+	res := openapi3.Callbacks{}
+
+	for _, cb := range cber.Callbacks() {
+		for _, ev := range cb.Events {
+			item, err := webhookPathItem(g.parser, g.reflector, ev, g.opts)
+			if err != nil {
+				return nil, err
+			}
+
+			res[ev.Name] = &openapi3.CallbackRef{Value: &openapi3.Callback{cb.URL: item}}
+		}
+	}
+
+	return res, nil
+}
+
+var webhookHeaders = []struct {
+	name        string
+	description string
+}{
+	{chai.WebhookIDHeader, "The ID of the delivery, which is the same for all its attempts"},
+	{chai.WebhookEventHeader, "The name of the event"},
+	{chai.WebhookTimestampHeader, "The Unix time at which the request was sent"},
+	{chai.WebhookSignatureHeader, `"sha256=" followed by the hex-encoded HMAC-SHA256 of the timestamp, a dot and the body, keyed with the webhook secret`},
+}
+
+// webhookPathItem returns the path item of the POST request an event is sent with. The definitions that swag parses
+// from the file the event is registered in, unless the schemas are built by reflection, describe the payload's components.
+func webhookPathItem(parser *swag.Parser, r *Reflector, ev chai.WebhookEvent, opts []openapi2.Option) (*openapi3.PathItem, error) {
+	v := reflect.New(ev.Type).Interface()
+
+	if _, err := openapi2.FileSchema(parser, ev.File, v, opts...); err != nil {
+		return nil, err
+	}
+
+	schema, err := r.Schema(v)
+	if err != nil {
+		return nil, err
+	}
+
+	op := openapi3.NewOperation()
+	op.OperationID = ev.Name
+	op.Summary = ev.Description
+
+	for _, h := range webhookHeaders {
+		op.AddParameter(openapi3.NewHeaderParameter(h.name).WithRequired(true).WithDescription(h.description).WithSchema(openapi3.NewStringSchema()))
+	}
+
+	op.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schema)}
+	op.Responses = openapi3.Responses{
+		"2XX": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("The delivery is acknowledged. Network errors, 408, 429 and 5xx responses are retried with backoff")},
+	}
+
+	return &openapi3.PathItem{Post: op}, nil
+}
@@ -0,0 +1,212 @@
+package openapi31
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/openapi2"
+	chaiopenapi3 "github.com/go-chai/chai/openapi3"
+)
+
+// Version is the version of the OpenAPI specification the documents are written in.
+const Version = "3.1.0"
+
+// Dialect is the JSON Schema dialect of the schemas of the documents, JSON Schema 2020-12 with the OpenAPI vocabulary.
+const Dialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// Document is an OpenAPI 3.1 document. The paths, webhooks and components are kept as JSON values,
+// since the types of kin-openapi cannot express the schemas of OpenAPI 3.1.
+type Document struct {
+	OpenAPI           string                        `json:"openapi"`
+	JSONSchemaDialect string                        `json:"jsonSchemaDialect,omitempty"`
+	Info              *openapi3.Info                `json:"info"`
+	Servers           openapi3.Servers              `json:"servers,omitempty"`
+	Paths             map[string]any                `json:"paths,omitempty"`
+	Webhooks          map[string]any                `json:"webhooks,omitempty"`
+	Components        map[string]any                `json:"components,omitempty"`
+	Security          openapi3.SecurityRequirements `json:"security,omitempty"`
+	Tags              openapi3.Tags                 `json:"tags,omitempty"`
+	ExternalDocs      *openapi3.ExternalDocs        `json:"externalDocs,omitempty"`
+}
+
+type Route = chaiopenapi3.Route
+
+// Docs documents the routes in an OpenAPI 3.1 document, see openapi3.Docs.
+func Docs(routes []*Route, opts ...openapi2.Option) (*Document, error) {
+	docs, err := chaiopenapi3.Docs(routes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromV3(docs)
+}
+
+// FromV3 converts an OpenAPI 3.0 document to OpenAPI 3.1. The schemas are converted to JSON Schema 2020-12:
+// nullable types become type arrays with "null", examples become arrays of examples, single value enums become consts
+// and boolean exclusive bounds become numeric ones. The webhooks of the `x-webhooks` extension are moved to the `webhooks` section.
+func FromV3(docs *openapi3.T) (*Document, error) {
+	b, err := json.Marshal(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	m["openapi"] = Version
+	m["jsonSchemaDialect"] = Dialect
+
+	if webhooks, ok := m["x-webhooks"]; ok {
+		m["webhooks"] = webhooks
+		delete(m, "x-webhooks")
+	}
+
+	if components, ok := m["components"].(map[string]any); ok {
+		if schemas, ok := components["schemas"].(map[string]any); ok {
+			for _, s := range schemas {
+				convertSchema(s)
+			}
+		}
+
+		for name, c := range components {
+			if name != "schemas" {
+				walk(c)
+			}
+		}
+	}
+
+	walk(m["paths"])
+	walk(m["webhooks"])
+
+	b, err = json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(Document)
+	if err := json.Unmarshal(b, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddWebhooks documents the events of wr in the webhooks section of doc, and adds the schemas of their payloads to its components.
+func AddWebhooks(doc *Document, wr *chai.WebhookRegistry, opts ...openapi2.Option) error {
+	docs3 := chaiopenapi3.New()
+
+	err := chaiopenapi3.AddWebhooks(docs3, wr, opts...)
+	if err != nil {
+		return err
+	}
+
+	res, err := FromV3(docs3)
+	if err != nil {
+		return err
+	}
+
+	for name, item := range res.Webhooks {
+		if doc.Webhooks == nil {
+			doc.Webhooks = map[string]any{}
+		}
+		doc.Webhooks[name] = item
+	}
+
+	schemas, _ := res.Components["schemas"].(map[string]any)
+	for name, s := range schemas {
+		if doc.Components == nil {
+			doc.Components = map[string]any{}
+		}
+
+		docSchemas, ok := doc.Components["schemas"].(map[string]any)
+		if !ok {
+			docSchemas = map[string]any{}
+			doc.Components["schemas"] = docSchemas
+		}
+
+		if _, ok := docSchemas[name]; !ok {
+			docSchemas[name] = s
+		}
+	}
+
+	return nil
+}
+
+// JSONSchema returns the component schema with the given name as a standalone JSON Schema 2020-12 document,
+// with the component schemas it refers to in its `$defs`. It returns nil if there is no such schema.
+func (doc *Document) JSONSchema(name string) map[string]any {
+	schemas, _ := doc.Components["schemas"].(map[string]any)
+	if _, ok := schemas[name]; !ok {
+		return nil
+	}
+
+	defs := map[string]any{}
+	addDefs(schemas, defs, schemas[name])
+
+	res := rewriteRefs(schemas[name]).(map[string]any)
+	res["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+
+	delete(defs, name)
+	if len(defs) > 0 {
+		res["$defs"] = defs
+	}
+
+	return res
+}
+
+const componentsPrefix = "#/components/schemas/"
+
+// addDefs adds the component schemas that v refers to, directly or not, to defs, with their references rewritten to point to defs.
+func addDefs(schemas, defs map[string]any, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, componentsPrefix) {
+			name := strings.TrimPrefix(ref, componentsPrefix)
+			if _, ok := defs[name]; !ok {
+				if s, ok := schemas[name]; ok {
+					defs[name] = rewriteRefs(s)
+					addDefs(schemas, defs, s)
+				}
+			}
+		}
+
+		for _, x := range v {
+			addDefs(schemas, defs, x)
+		}
+	case []any:
+		for _, x := range v {
+			addDefs(schemas, defs, x)
+		}
+	}
+}
+
+// rewriteRefs returns a copy of v whose references to component schemas point to $defs.
+func rewriteRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, x := range v {
+			if ref, ok := x.(string); ok && k == "$ref" {
+				res[k] = strings.Replace(ref, componentsPrefix, "#/$defs/", 1)
+				continue
+			}
+
+			res[k] = rewriteRefs(x)
+		}
+
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, x := range v {
+			res[i] = rewriteRefs(x)
+		}
+
+		return res
+	default:
+		return v
+	}
+}
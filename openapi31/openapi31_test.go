@@ -0,0 +1,126 @@
+package openapi31
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromV3(t *testing.T) {
+	one, ten := 1.0, 10.0
+
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+		want   string
+	}{
+		{
+			name:   "nullable type",
+			schema: &openapi3.Schema{Type: "string", Nullable: true},
+			want:   `{"type": ["string", "null"]}`,
+		},
+		{
+			name:   "nullable without a type",
+			schema: &openapi3.Schema{Nullable: true, Description: "Anything"},
+			want:   `{"anyOf": [{"description": "Anything"}, {"type": "null"}]}`,
+		},
+		{
+			name:   "not nullable",
+			schema: &openapi3.Schema{Type: "string", Nullable: false},
+			want:   `{"type": "string"}`,
+		},
+		{
+			name:   "exclusive bounds",
+			schema: &openapi3.Schema{Type: "number", Min: &one, ExclusiveMin: true, Max: &ten, ExclusiveMax: true},
+			want:   `{"type": "number", "exclusiveMinimum": 1, "exclusiveMaximum": 10}`,
+		},
+		{
+			name:   "inclusive bounds",
+			schema: &openapi3.Schema{Type: "number", Min: &one, Max: &ten},
+			want:   `{"type": "number", "minimum": 1, "maximum": 10}`,
+		},
+		{
+			name:   "example",
+			schema: &openapi3.Schema{Type: "integer", Example: 3},
+			want:   `{"type": "integer", "examples": [3]}`,
+		},
+		{
+			name:   "single value enum",
+			schema: &openapi3.Schema{Type: "string", Enum: []any{"a"}},
+			want:   `{"type": "string", "const": "a"}`,
+		},
+		{
+			name: "subschemas",
+			schema: openapi3.NewObjectSchema().
+				WithProperty("name", &openapi3.Schema{Type: "string", Nullable: true, Example: "foo"}).
+				WithProperty("tags", openapi3.NewArraySchema().WithItems(&openapi3.Schema{Type: "integer", Min: &one, ExclusiveMin: true})),
+			want: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": ["string", "null"], "examples": ["foo"]},
+					"tags": {"type": "array", "items": {"type": "integer", "exclusiveMinimum": 1}}
+				}
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := &openapi3.T{
+				OpenAPI: "3.0.3",
+				Info:    &openapi3.Info{Title: "t", Version: "1"},
+				Paths:   openapi3.Paths{},
+				Components: openapi3.Components{
+					Schemas: openapi3.Schemas{"S": openapi3.NewSchemaRef("", tt.schema)},
+				},
+			}
+
+			got, err := FromV3(docs)
+			require.NoError(t, err)
+
+			require.Equal(t, Version, got.OpenAPI)
+			require.Equal(t, Dialect, got.JSONSchemaDialect)
+
+			b, err := json.Marshal(got.Components["schemas"].(map[string]any)["S"])
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestFromV3Paths(t *testing.T) {
+	docs := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "t", Version: "1"},
+		Paths: openapi3.Paths{
+			"/items": &openapi3.PathItem{
+				Get: &openapi3.Operation{
+					Parameters: openapi3.Parameters{
+						{Value: openapi3.NewQueryParameter("q").WithSchema(&openapi3.Schema{Type: "string", Nullable: true})},
+					},
+					Responses: openapi3.NewResponses(),
+				},
+			},
+		},
+		ExtensionProps: openapi3.ExtensionProps{Extensions: map[string]any{
+			"x-webhooks": map[string]any{"created": map[string]any{}},
+		}},
+	}
+
+	got, err := FromV3(docs)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(got.Paths)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"/items": {
+			"get": {
+				"parameters": [{"in": "query", "name": "q", "schema": {"type": ["string", "null"]}}],
+				"responses": {"default": {"description": ""}}
+			}
+		}
+	}`, string(b))
+
+	require.Equal(t, map[string]any{"created": map[string]any{}}, got.Webhooks)
+}
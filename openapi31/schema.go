@@ -0,0 +1,100 @@
+package openapi31
+
+// walk converts the schemas found in v, the JSON value of a part of an OpenAPI 3.0 document, to JSON Schema 2020-12.
+// The schemas are the values of "schema" keys, e.g. those of parameters, media types and headers.
+func walk(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if s, ok := x.(map[string]any); ok && k == "schema" {
+				convertSchema(s)
+				continue
+			}
+
+			walk(x)
+		}
+	case []any:
+		for _, x := range v {
+			walk(x)
+		}
+	}
+}
+
+// convertSchema converts an OpenAPI 3.0 schema and its subschemas to JSON Schema 2020-12, in place.
+func convertSchema(v any) {
+	s, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if nullable, ok := s["nullable"].(bool); ok {
+		delete(s, "nullable")
+
+		if nullable {
+			setNullable(s)
+		}
+	}
+
+	if example, ok := s["example"]; ok {
+		delete(s, "example")
+		s["examples"] = []any{example}
+	}
+
+	if enum, ok := s["enum"].([]any); ok && len(enum) == 1 {
+		delete(s, "enum")
+		s["const"] = enum[0]
+	}
+
+	exclusiveBound(s, "exclusiveMinimum", "minimum")
+	exclusiveBound(s, "exclusiveMaximum", "maximum")
+
+	if props, ok := s["properties"].(map[string]any); ok {
+		for _, p := range props {
+			convertSchema(p)
+		}
+	}
+
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if subs, ok := s[key].([]any); ok {
+			for _, sub := range subs {
+				convertSchema(sub)
+			}
+		}
+	}
+
+	convertSchema(s["items"])
+	convertSchema(s["not"])
+	convertSchema(s["additionalProperties"])
+}
+
+// setNullable adds "null" to the types of the schema, or makes it any of itself and null if it has no type.
+func setNullable(s map[string]any) {
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []any{t, "null"}
+
+		return
+	}
+
+	inner := make(map[string]any, len(s))
+	for k, v := range s {
+		inner[k] = v
+		delete(s, k)
+	}
+
+	s["anyOf"] = []any{inner, map[string]any{"type": "null"}}
+}
+
+// exclusiveBound turns the boolean exclusive bound of OpenAPI 3.0, which applies to the bound, into the numeric one of JSON Schema 2020-12.
+func exclusiveBound(s map[string]any, exclusiveKey, boundKey string) {
+	exclusive, ok := s[exclusiveKey].(bool)
+	if !ok {
+		return
+	}
+
+	delete(s, exclusiveKey)
+
+	if bound, ok := s[boundKey]; ok && exclusive {
+		s[exclusiveKey] = bound
+		delete(s, boundKey)
+	}
+}
@@ -0,0 +1,11 @@
+package chai
+
+import (
+	"github.com/go-chai/chai/asyncapi"
+	"github.com/go-chai/chai/openapi2"
+)
+
+// AsyncAPI documents the Server-Sent Events and WebSocket routes of r.
+func AsyncAPI(r *Router, opts ...openapi2.Option) (*asyncapi.Document, error) {
+	return asyncapi.Docs(r.Routes(), opts...)
+}
@@ -0,0 +1,187 @@
+package chai
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/openapi2"
+)
+
+// Router is an *http.ServeMux that records the routes registered through it, since a ServeMux cannot be walked,
+// and whose chai handlers inherit the options of a Config.
+type Router struct {
+	*http.ServeMux
+	config *chai.Config
+
+	mu     sync.Mutex
+	routes []*openapi2.Route
+}
+
+// NewRouter wraps mux so that the routes registered through the returned Router are documented,
+// and the handlers registered through it use the options of config.
+func NewRouter(mux *http.ServeMux, config *chai.Config) *Router {
+	return &Router{ServeMux: mux, config: config}
+}
+
+func (r *Router) Config() *chai.Config {
+	return r.config
+}
+
+// Method registers h for the requests with the given method that match the pattern, e.g. "/items/{id}".
+func (r *Router) Method(method, pattern string, h http.Handler) {
+	r.Handle(method+" "+pattern, h)
+}
+
+// Handle registers h for the pattern, e.g. "GET /items/{id}", like http.ServeMux.Handle, and records the route.
+func (r *Router) Handle(pattern string, h http.Handler) {
+	r.ServeMux.Handle(pattern, h)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	method, params, path := ParsePattern(pattern)
+	r.routes = append(r.routes, &openapi2.Route{
+		Method:  method,
+		Path:    path,
+		Params:  params,
+		Handler: h,
+	})
+}
+
+// HandleFunc registers fn for the pattern like http.ServeMux.HandleFunc, and records the route.
+func (r *Router) HandleFunc(pattern string, fn func(http.ResponseWriter, *http.Request)) {
+	r.Handle(pattern, http.HandlerFunc(fn))
+}
+
+// MethodlessRouteMethods are the methods the chai handlers of the patterns without a method are documented for,
+// since they match every method. Those patterns are not documented if it is empty, nor when their handlers are not chai handlers,
+// e.g. file servers.
+var MethodlessRouteMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// Routes returns the routes registered through r. The patterns without a method are documented for MethodlessRouteMethods.
+func (r *Router) Routes() []*openapi2.Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]*openapi2.Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		if route.Method != "" {
+			res = append(res, route)
+			continue
+		}
+
+		if _, ok := route.Handler.(chai.Handlerer); !ok {
+			continue
+		}
+
+		for _, method := range MethodlessRouteMethods {
+			methodRoute := *route
+			methodRoute.Method = method
+			methodRoute.Params = slices.Clone(route.Params)
+			res = append(res, &methodRoute)
+		}
+	}
+
+	return res
+}
+
+// WalkRoutes calls fn for the routes of r, see Routes, in the order they were registered, see openapi2.RouteWalker.
+func (r *Router) WalkRoutes(fn openapi2.WalkFunc) error {
+	for _, route := range r.Routes() {
+		if err := fn(route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handlerOptions returns the options of a handler registered on r: the ServeMux's path values, then r's Config, then opts and extra.
+func handlerOptions(r chai.Methoder, opts []chai.Option, extra ...chai.Option) []chai.Option {
+	res := []chai.Option{chai.WithPathParamFunc(pathParam), chai.WithConfig(chai.ConfigOf(r))}
+	res = append(res, opts...)
+
+	return append(res, extra...)
+}
+
+func Get[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Head[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodHead, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Connect[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodConnect, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Options[Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ResHandlerFunc[Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodOptions, path, chai.NewResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Post[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPost, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Put[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPut, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Patch[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPatch, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+func Delete[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodDelete, path, chai.NewReqResHandler(fn, handlerOptions(r, opts)...))
+}
+
+// GetReq registers a GET handler whose request type is filled only from the path, query, headers and cookies.
+func GetReq[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// HeadReq registers a HEAD handler whose request type is filled only from the path, query, headers and cookies.
+func HeadReq[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodHead, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// DeleteReq registers a DELETE handler whose request type is filled only from the path, query, headers and cookies.
+func DeleteReq[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodDelete, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// SSE registers a GET handler that streams Server-Sent Events. Its request type is filled only from the path, query, headers and cookies.
+func SSE[Req any, T any, Err chai.ErrType](r chai.Methoder, path string, fn chai.SSEHandlerFunc[Req, T, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewSSEHandler(fn, handlerOptions(r, opts)...))
+}
+
+// Stream registers a GET handler that streams its items as application/x-ndjson or a JSON array. Its request type is filled only from the path, query, headers and cookies.
+func Stream[Req any, T any, Err chai.ErrType](r chai.Methoder, path string, fn chai.StreamHandlerFunc[Req, T, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewStreamHandler(fn, handlerOptions(r, opts, chai.WithoutBody())...))
+}
+
+// WebSocket registers a GET handler that upgrades the requests to WebSocket connections on which messages of type In are received
+// and messages of type Out are sent as JSON. Its request type is filled only from the path, query, headers and cookies.
+func WebSocket[Req any, In any, Out any, Err chai.ErrType](r chai.Methoder, path string, fn chai.WebSocketHandlerFunc[Req, In, Out, Err], opts ...chai.Option) {
+	r.Method(http.MethodGet, path, chai.NewWebSocketHandler(fn, handlerOptions(r, opts)...))
+}
+
+// Upload registers a POST handler whose request type is decoded from a multipart/form-data body, see chai.MultipartFields.
+func Upload[Req any, Res any, Err chai.ErrType](r chai.Methoder, path string, fn chai.ReqResHandlerFunc[Req, Res, Err], opts ...chai.Option) {
+	r.Method(http.MethodPost, path, chai.NewReqResHandler(fn, handlerOptions(r, opts, chai.WithMultipart())...))
+}
+
+func pathParam(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
@@ -0,0 +1,86 @@
+package chai
+
+import (
+	"strings"
+
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-openapi/spec"
+)
+
+func OpenAPI2(r *Router, opts ...openapi2.Option) (*spec.Swagger, error) {
+	return openapi2.Docs(r.Routes(), opts...)
+}
+
+// PrefixParam is the name of the path parameter the rest of the path matched by the patterns that end in a slash is documented as,
+// e.g. "/files/" as "/files/{path}", or "*" if the pattern has a wildcard with that name. ServeMux does not set it, so the handlers
+// of those patterns read the rest of the path from the request's URL. The patterns are documented as exact paths if it is empty.
+var PrefixParam = "path"
+
+// ParsePattern parses a ServeMux pattern, "[METHOD ][HOST]/[PATH]", into its method, the parameters of its wildcards and its path.
+// The "{name}" and "{name...}" wildcards are both path parameters named name, and the "{$}" wildcard, which only anchors the end of the path, is removed.
+// The patterns that end in a slash match every path with that prefix, and the rest of the path is the PrefixParam path parameter.
+// The host is not part of the path. The method is empty if the pattern has none.
+func ParsePattern(pattern string) (method string, params []spec.Parameter, path string) {
+	method, rest, found := strings.Cut(strings.TrimLeft(pattern, " \t"), " ")
+	if !found {
+		method, rest = "", method
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		rest = rest[i:]
+	}
+
+	params = make([]spec.Parameter, 0)
+
+	for {
+		before, after, found := strings.Cut(rest, "{")
+		path += before
+
+		if !found {
+			break
+		}
+
+		name, after, _ := strings.Cut(after, "}")
+		rest = after
+
+		if name == "$" {
+			continue
+		}
+
+		name = strings.TrimSuffix(name, "...")
+		path += "{" + name + "}"
+
+		params = append(params, pathParameter(name))
+	}
+
+	if PrefixParam != "" && strings.HasSuffix(path, "/") && !strings.HasSuffix(pattern, "{$}") {
+		name := PrefixParam
+		for _, p := range params {
+			if p.Name == name {
+				name = "*"
+			}
+		}
+
+		param := pathParameter(name)
+		param.Description = "The rest of the path"
+
+		path += "{" + name + "}"
+		params = append(params, param)
+	}
+
+	return method, params, path
+}
+
+func pathParameter(name string) spec.Parameter {
+	return spec.Parameter{
+		ParamProps: spec.ParamProps{
+			Name:     name,
+			In:       "path",
+			Required: true,
+		},
+		SimpleSchema: spec.SimpleSchema{
+			Type: "string",
+		},
+	}
+}
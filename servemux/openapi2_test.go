@@ -0,0 +1,116 @@
+package chai_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chaicore "github.com/go-chai/chai/chai"
+	"github.com/go-chai/chai/internal/tests"
+	chai "github.com/go-chai/chai/servemux"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePattern(t *testing.T) {
+	pathParam := func(name string) spec.Parameter {
+		return spec.Parameter{
+			ParamProps:   spec.ParamProps{Name: name, In: "path", Required: true},
+			SimpleSchema: spec.SimpleSchema{Type: "string"},
+		}
+	}
+
+	restParam := func(name string) spec.Parameter {
+		p := pathParam(name)
+		p.Description = "The rest of the path"
+		return p
+	}
+
+	tcs := []struct {
+		pattern    string
+		wantMethod string
+		wantParams []spec.Parameter
+		wantPath   string
+	}{
+		{pattern: "/items", wantParams: []spec.Parameter{}, wantPath: "/items"},
+		{pattern: "GET /items/{id}", wantMethod: "GET", wantParams: []spec.Parameter{pathParam("id")}, wantPath: "/items/{id}"},
+		{pattern: "POST  /items/{id}/tags/{tag}", wantMethod: "POST", wantParams: []spec.Parameter{pathParam("id"), pathParam("tag")}, wantPath: "/items/{id}/tags/{tag}"},
+		{pattern: "GET /files/{path...}", wantMethod: "GET", wantParams: []spec.Parameter{pathParam("path")}, wantPath: "/files/{path}"},
+		{pattern: "GET /{$}", wantMethod: "GET", wantParams: []spec.Parameter{}, wantPath: "/"},
+		{pattern: "GET example.com/items/{id}", wantMethod: "GET", wantParams: []spec.Parameter{pathParam("id")}, wantPath: "/items/{id}"},
+		{pattern: "GET /files/", wantMethod: "GET", wantParams: []spec.Parameter{restParam("path")}, wantPath: "/files/{path}"},
+		{pattern: "/", wantParams: []spec.Parameter{restParam("path")}, wantPath: "/{path}"},
+		{pattern: "GET /{path}/", wantMethod: "GET", wantParams: []spec.Parameter{pathParam("path"), restParam("*")}, wantPath: "/{path}/{*}"},
+		{pattern: "GET /files/{$}", wantMethod: "GET", wantParams: []spec.Parameter{}, wantPath: "/files/"},
+	}
+	for _, tt := range tcs {
+		t.Run(tt.pattern, func(t *testing.T) {
+			method, params, path := chai.ParsePattern(tt.pattern)
+			require.Equal(t, tt.wantMethod, method)
+			require.Equal(t, tt.wantParams, params)
+			require.Equal(t, tt.wantPath, path)
+		})
+	}
+}
+
+func TestRouter(t *testing.T) {
+	r := chai.NewRouter(http.NewServeMux(), nil)
+
+	chai.Post(r, "/items/{id}", func(req *tests.TestParamsRequest, w http.ResponseWriter, r *http.Request) (map[string]any, int, error) {
+		return map[string]any{"id": req.ID, "foo": req.Foo}, http.StatusOK, nil
+	})
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items/42", strings.NewReader(`{"foo":"bar"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"id":42,"foo":"bar"}`, w.Body.String())
+
+	docs, err := chai.OpenAPI2(r)
+	require.NoError(t, err)
+	require.Len(t, docs.Paths.Paths, 1)
+
+	op := docs.Paths.Paths["/items/{id}"].Post
+	require.NotNil(t, op)
+
+	var id *spec.Parameter
+	for i, p := range op.Parameters {
+		if p.In == "path" && p.Name == "id" {
+			id = &op.Parameters[i]
+		}
+	}
+	require.NotNil(t, id)
+	require.Equal(t, "integer", id.Type)
+}
+
+func TestRouterMethodlessAndPrefixPatterns(t *testing.T) {
+	defer func(methods []string) { chai.MethodlessRouteMethods = methods }(chai.MethodlessRouteMethods)
+
+	chai.MethodlessRouteMethods = []string{http.MethodGet, http.MethodPost}
+
+	r := chai.NewRouter(http.NewServeMux(), nil)
+
+	r.Handle("/items/{id}", chaicore.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestResponse, int, error) {
+		return nil, 0, nil
+	}))
+	r.Handle("GET /files/", chaicore.NewResHandler(func(w http.ResponseWriter, r *http.Request) (*tests.TestInnerResponse, int, error) {
+		return nil, 0, nil
+	}))
+	r.Handle("/static/", http.FileServer(http.Dir("testdata")))
+
+	docs, err := chai.OpenAPI2(r)
+	require.NoError(t, err)
+	require.Len(t, docs.Paths.Paths, 2)
+
+	items := docs.Paths.Paths["/items/{id}"]
+	require.NotNil(t, items.Get)
+	require.NotNil(t, items.Post)
+	require.Nil(t, items.Put)
+
+	files := docs.Paths.Paths["/files/{path}"].Get
+	require.NotNil(t, files)
+	require.Len(t, files.Parameters, 1)
+	require.Equal(t, "path", files.Parameters[0].Name)
+	require.Equal(t, "path", files.Parameters[0].In)
+}
@@ -0,0 +1,11 @@
+package chai
+
+import (
+	kinopenapi3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/chai/openapi3"
+)
+
+func OpenAPI3(r *Router, opts ...openapi2.Option) (*kinopenapi3.T, error) {
+	return openapi3.Docs(r.Routes(), opts...)
+}
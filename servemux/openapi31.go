@@ -0,0 +1,11 @@
+package chai
+
+import (
+	"github.com/go-chai/chai/openapi2"
+	"github.com/go-chai/chai/openapi31"
+)
+
+// OpenAPI31 documents the routes of r in an OpenAPI 3.1 document.
+func OpenAPI31(r *Router, opts ...openapi2.Option) (*openapi31.Document, error) {
+	return openapi31.Docs(r.Routes(), opts...)
+}